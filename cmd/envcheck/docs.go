@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+// Names of docs formats accepted by -format
+var docsFormats = map[string]internal.DocsFormat{
+	"md":       internal.MarkdownDocs,
+	"markdown": internal.MarkdownDocs,
+	"html":     internal.HTMLDocs,
+}
+
+// docsCommand renders documentation of the vars declared in the schema
+func docsCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("docs", flag.ContinueOnError)
+	schemaPath := fs.String("schema", "",
+		"path to the schema (default .env.yaml in the project root)")
+	formatName := fs.String("format", "md", "output format: md or html")
+	title := fs.String("title", "Environment variables", "title of the page")
+	output := fs.String("o", "-", `file to write the documentation to, "-" for stdout`)
+	if code, ok := parseFlags(fs, args, stderr); !ok {
+		return code
+	}
+
+	format, ok := docsFormats[*formatName]
+	if !ok {
+		fmt.Fprintf(stderr, "envcheck docs: unsupported format %q, use md or html\n", *formatName)
+		return exitUsage
+	}
+
+	config, err := readSchema(*schemaPath)
+	if err != nil {
+		return report(stderr, fs.Name(), exitSchema, err)
+	}
+
+	docs, err := internal.RenderDocs(&config, format, *title)
+	if err != nil {
+		return report(stderr, fs.Name(), exitSchema, err)
+	}
+
+	if *output == "-" {
+		_, _ = io.WriteString(stdout, docs)
+		return exitOK
+	}
+	if err := os.WriteFile(*output, []byte(docs), 0o644); err != nil {
+		return report(stderr, fs.Name(), exitOutput, errors.Join(
+			fmt.Errorf("failed to write %s", *output),
+			err,
+		))
+	}
+	return exitOK
+}
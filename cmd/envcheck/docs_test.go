@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDocsCommand(t *testing.T) {
+	const schema = `
+vars:
+  - name: PORT
+    type: int
+    default_value: 8080
+    group: Server
+  - name: API_TOKEN
+    default_value: very-secret
+    secret: true
+    group: Auth
+`
+
+	testCases := []struct {
+		name           string
+		args           []string
+		expectedCode   int
+		expectedOutput []string
+	}{
+		{
+			name:         "Markdown",
+			args:         nil,
+			expectedCode: exitOK,
+			expectedOutput: []string{
+				"# Environment variables\n",
+				"## Server\n",
+				"| `PORT` | int |",
+			},
+		},
+		{
+			name:           "HTML with title",
+			args:           []string{"-format", "html", "-title", "Service"},
+			expectedCode:   exitOK,
+			expectedOutput: []string{"<title>Service</title>", "<h2>Auth</h2>"},
+		},
+		{
+			name:           "Unsupported format",
+			args:           []string{"-format", "pdf"},
+			expectedCode:   exitUsage,
+			expectedOutput: []string{"unsupported format \"pdf\""},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			createFiles(t, map[string]string{".env.yaml": schema})
+
+			code, stdout, stderr := runCLI(t, append([]string{"docs"}, tc.args...)...)
+			if code != tc.expectedCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedCode, code, stderr)
+			}
+			for _, expectedOutput := range tc.expectedOutput {
+				if !strings.Contains(stdout+stderr, expectedOutput) {
+					t.Errorf("expected output to contain \"%s\", got \"%s%s\"",
+						expectedOutput, stdout, stderr)
+				}
+			}
+			if strings.Contains(stdout, "very-secret") {
+				t.Error("default value of secret var must not be printed")
+			}
+		})
+	}
+
+	t.Run("Documentation is written to file", func(t *testing.T) {
+		createFiles(t, map[string]string{".env.yaml": schema})
+
+		code, _, stderr := runCLI(t, "docs", "-o", "ENV.md")
+		if code != exitOK {
+			t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+		}
+		data, err := os.ReadFile("ENV.md")
+		if err != nil || !strings.Contains(string(data), "PORT") {
+			t.Errorf("unexpected ENV.md content \"%s\", error %v", data, err)
+		}
+	})
+
+	t.Run("Documentation can not be written", func(t *testing.T) {
+		createFiles(t, map[string]string{".env.yaml": schema})
+
+		code, _, stderr := runCLI(t, "docs", "-o", "missing/ENV.md")
+		if code != exitOutput {
+			t.Errorf("expected exit code %d, got %d: %s", exitOutput, code, stderr)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+const defaultExampleFile = ".env.example"
+
+/*
+exampleCommand renders .env.example from the schema. With -check it does not write anything and
+fails if the existing file differs from what would be rendered, so CI can detect drift.
+*/
+func exampleCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("example", flag.ContinueOnError)
+	schemaPath := fs.String("schema", "",
+		"path to the schema (default .env.yaml in the project root)")
+	output := fs.String("o", defaultExampleFile, `file to write the example to, "-" for stdout`)
+	check := fs.Bool("check", false,
+		"fail if the file does not match the schema instead of writing it")
+	if code, ok := parseFlags(fs, args, stderr); !ok {
+		return code
+	}
+
+	config, err := readSchema(*schemaPath)
+	if err != nil {
+		return report(stderr, fs.Name(), exitSchema, err)
+	}
+	example := internal.RenderExample(&config)
+
+	if *check {
+		current, err := os.ReadFile(*output)
+		if err != nil {
+			return report(stderr, fs.Name(), exitEnvFile, err)
+		}
+		if line, differs := firstDifference(string(current), example); differs {
+			err := fmt.Errorf(
+				"%s does not match the schema at line %d, run \"envcheck example\" to update it",
+				*output,
+				line,
+			)
+			return report(stderr, fs.Name(), exitInvalid, err)
+		}
+		fmt.Fprintf(stdout, "%s is up to date\n", *output)
+		return exitOK
+	}
+
+	if *output == "-" {
+		_, _ = io.WriteString(stdout, example)
+		return exitOK
+	}
+	if err := os.WriteFile(*output, []byte(example), 0o644); err != nil {
+		return report(stderr, fs.Name(), exitOutput, errors.Join(
+			fmt.Errorf("failed to write %s", *output),
+			err,
+		))
+	}
+	fmt.Fprintf(stdout, "wrote %s\n", *output)
+	return exitOK
+}
+
+// firstDifference returns number of the first line which differs in a and b
+func firstDifference(a, b string) (int, bool) {
+	linesA := strings.Split(a, "\n")
+	linesB := strings.Split(b, "\n")
+
+	for i := 0; i < len(linesA) || i < len(linesB); i++ {
+		if i >= len(linesA) || i >= len(linesB) || linesA[i] != linesB[i] {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
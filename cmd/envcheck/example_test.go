@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExampleCommand(t *testing.T) {
+	const schema = `
+vars:
+  - name: PORT
+    type: int
+    default_value: 8080
+    description: Port of the HTTP server
+  - name: API_TOKEN
+    required: true
+    secret: true
+`
+
+	t.Run("Example is written and checked", func(t *testing.T) {
+		createFiles(t, map[string]string{".env.yaml": schema})
+
+		code, _, stderr := runCLI(t, "example")
+		if code != exitOK {
+			t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+		}
+
+		data, err := os.ReadFile(".env.example")
+		if err != nil {
+			t.Fatalf("failed to read .env.example: %v", err)
+		}
+		expectedLines := []string{
+			"# Port of the HTTP server\n",
+			"PORT=8080\n",
+			"API_TOKEN=CHANGE_ME\n",
+		}
+		for _, expected := range expectedLines {
+			if !strings.Contains(string(data), expected) {
+				t.Errorf("expected .env.example to contain \"%s\", got \"%s\"", expected, data)
+			}
+		}
+
+		code, stdout, stderr := runCLI(t, "example", "-check")
+		if code != exitOK || !strings.Contains(stdout, "is up to date") {
+			t.Errorf("expected .env.example to be up to date, got %d: %s", code, stderr)
+		}
+
+		err = os.WriteFile(".env.yaml", []byte(schema+"  - name: NEW_VAR\n"), 0o644)
+		if err != nil {
+			t.Fatalf("failed to update .env.yaml: %v", err)
+		}
+
+		code, _, stderr = runCLI(t, "example", "-check")
+		if code != exitInvalid || !strings.Contains(stderr, "does not match the schema at line") {
+			t.Errorf("expected drift to be detected, got %d: %s", code, stderr)
+		}
+	})
+
+	t.Run("Example is written to stdout", func(t *testing.T) {
+		createFiles(t, map[string]string{".env.yaml": schema})
+
+		code, stdout, stderr := runCLI(t, "example", "-o", "-")
+		if code != exitOK || !strings.Contains(stdout, "PORT=8080") {
+			t.Errorf("unexpected result %d: %s%s", code, stdout, stderr)
+		}
+	})
+
+	t.Run("Example to check does not exist", func(t *testing.T) {
+		createFiles(t, map[string]string{".env.yaml": schema})
+
+		code, _, stderr := runCLI(t, "example", "-check")
+		if code != exitEnvFile {
+			t.Errorf("expected exit code %d, got %d: %s", exitEnvFile, code, stderr)
+		}
+	})
+
+	t.Run("Example can not be written", func(t *testing.T) {
+		createFiles(t, map[string]string{".env.yaml": schema})
+
+		code, _, stderr := runCLI(t, "example", "-o", "missing/.env.example")
+		if code != exitOutput {
+			t.Errorf("expected exit code %d, got %d: %s", exitOutput, code, stderr)
+		}
+	})
+}
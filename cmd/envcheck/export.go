@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+// Names of export formats accepted by -format
+var exportFormats = map[string]internal.ExportFormat{
+	"sh":         internal.ShellExport,
+	"fish":       internal.FishExport,
+	"powershell": internal.PowerShellExport,
+	"docker":     internal.DockerExport,
+	"systemd":    internal.SystemdExport,
+	"json":       internal.JSONExport,
+	"yaml":       internal.YAMLExport,
+	"github":     internal.GitHubExport,
+	"kubernetes": internal.KubernetesExport,
+	"k8s":        internal.KubernetesExport,
+}
+
+/*
+exportCommand resolves and validates the environment like run does and prints the declared vars
+in the format of another tool, so the same configuration can be handed over to it:
+
+	eval "$(envcheck export -format sh)"
+	envcheck export -format github >> "$GITHUB_ENV"
+	envcheck export -format kubernetes -name api | kubectl apply -f -
+*/
+func exportCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	schemaPath := fs.String("schema", "",
+		"path to the schema (default .env.yaml in the project root)")
+	var envFiles stringList
+	fs.Var(&envFiles, "env-file",
+		".env file to load instead of the .env cascade, may be repeated")
+	failFast := fs.Bool("fail-fast", false,
+		"stop at the first failing stage instead of reporting all problems")
+	formatName := fs.String("format", "sh", "output format: sh, fish, powershell, docker, "+
+		"systemd, json, yaml, github or kubernetes")
+	name := fs.String("name", "env", "name of the kubernetes ConfigMap and Secret")
+	namespace := fs.String("namespace", "", "namespace of the kubernetes ConfigMap and Secret")
+	output := fs.String("o", "-", `file to write the export to, "-" for stdout`)
+	if code, ok := parseFlags(fs, args, stderr); !ok {
+		return code
+	}
+
+	format, ok := exportFormats[*formatName]
+	if !ok {
+		fmt.Fprintf(stderr, "envcheck export: unsupported format %q\n", *formatName)
+		return exitUsage
+	}
+
+	config, err := readSchema(*schemaPath)
+	if err != nil {
+		return report(stderr, fs.Name(), exitSchema, err)
+	}
+
+	resolved, code, err := resolveEnv(&config, envFiles, *failFast)
+	if err != nil {
+		return report(stderr, fs.Name(), code, err)
+	}
+
+	export, err := internal.RenderExport(&config, resolved, format, internal.ExportOptions{
+		Name:      *name,
+		Namespace: *namespace,
+	})
+	if err != nil {
+		return report(stderr, fs.Name(), exitInvalid, err)
+	}
+
+	if *output == "-" {
+		_, _ = io.WriteString(stdout, export)
+		return exitOK
+	}
+	// The export may hold secrets, so it is readable only by the owner
+	if err := os.WriteFile(*output, []byte(export), 0o600); err != nil {
+		return report(stderr, fs.Name(), exitOutput, errors.Join(
+			fmt.Errorf("failed to write %s", *output),
+			err,
+		))
+	}
+	return exitOK
+}
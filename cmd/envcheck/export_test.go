@@ -0,0 +1,130 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExportCommand(t *testing.T) {
+	const schema = `
+vars:
+  - name: ENVCHECK_TEST_PORT
+    required: true
+    type: int
+  - name: ENVCHECK_TEST_TOKEN
+    type: string
+    secret: true
+    default_value: "it's"
+`
+	files := map[string]string{".env.yaml": schema, ".env": "ENVCHECK_TEST_PORT=80"}
+
+	testCases := []struct {
+		name           string
+		files          map[string]string
+		env            map[string]string
+		args           []string
+		expectedCode   int
+		expectedOutput []string
+	}{
+		{
+			name:         "POSIX shell by default",
+			files:        files,
+			expectedCode: exitOK,
+			expectedOutput: []string{
+				"export ENVCHECK_TEST_PORT='80'\nexport ENVCHECK_TEST_TOKEN='it'\\''s'\n",
+			},
+		},
+		{
+			name:         "Kubernetes",
+			files:        files,
+			args:         []string{"-format", "k8s", "-name", "api", "-namespace", "prod"},
+			expectedCode: exitOK,
+			expectedOutput: []string{
+				"kind: ConfigMap\nmetadata:\n  name: api\n  namespace: prod\n",
+				"data:\n  ENVCHECK_TEST_PORT: \"80\"\n",
+				"kind: Secret",
+				"ENVCHECK_TEST_TOKEN: aXQncw==",
+			},
+		},
+		{
+			name: "Env files instead of cascade",
+			files: map[string]string{
+				".env.yaml": schema,
+				"app.env":   "ENVCHECK_TEST_PORT=81\nUNDECLARED=1",
+			},
+			args:           []string{"-env-file", "app.env", "-format", "docker"},
+			expectedCode:   exitOK,
+			expectedOutput: []string{"ENVCHECK_TEST_PORT=81\nENVCHECK_TEST_TOKEN=it's\n"},
+		},
+		{
+			name:           "Process environment without .env",
+			files:          map[string]string{".env.yaml": schema},
+			env:            map[string]string{"ENVCHECK_TEST_PORT": "82"},
+			args:           []string{"-format", "docker"},
+			expectedCode:   exitOK,
+			expectedOutput: []string{"ENVCHECK_TEST_PORT=82\n"},
+		},
+		{
+			name:           "Invalid environment",
+			files:          map[string]string{".env.yaml": schema, ".env": ""},
+			expectedCode:   exitInvalid,
+			expectedOutput: []string{"required var ENVCHECK_TEST_PORT is not set"},
+		},
+		{
+			name:           "Unsupported format",
+			files:          files,
+			args:           []string{"-format", "toml"},
+			expectedCode:   exitUsage,
+			expectedOutput: []string{"unsupported format \"toml\""},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			createFiles(t, tc.files)
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+
+			code, stdout, stderr := runCLI(t, append([]string{"export"}, tc.args...)...)
+			if code != tc.expectedCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedCode, code, stderr)
+			}
+			for _, expectedOutput := range tc.expectedOutput {
+				if !strings.Contains(stdout+stderr, expectedOutput) {
+					t.Errorf("expected output to contain \"%s\", got \"%s%s\"",
+						expectedOutput, stdout, stderr)
+				}
+			}
+			if strings.Contains(stdout, "UNDECLARED") {
+				t.Error("vars which are not declared must not be exported")
+			}
+		})
+	}
+
+	t.Run("Export is written to file", func(t *testing.T) {
+		createFiles(t, files)
+
+		code, _, stderr := runCLI(t, "export", "-format", "json", "-o", "env.json")
+		if code != exitOK {
+			t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+		}
+		data, err := os.ReadFile("env.json")
+		if err != nil || !strings.Contains(string(data), `"ENVCHECK_TEST_PORT": "80"`) {
+			t.Errorf("unexpected env.json content \"%s\", error %v", data, err)
+		}
+	})
+
+	t.Run("Export can not be written", func(t *testing.T) {
+		createFiles(t, files)
+
+		code, _, stderr := runCLI(t, "export", "-o", "missing/env.sh")
+		if code != exitOutput {
+			t.Errorf("expected exit code %d, got %d: %s", exitOutput, code, stderr)
+		}
+		if !strings.Contains(stderr, "failed to write missing/env.sh") {
+			t.Errorf("unexpected error output \"%s\"", stderr)
+		}
+	})
+}
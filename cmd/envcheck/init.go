@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+const initHeader = `# Draft schema generated by "envcheck init", review it before use.
+# Vars set in every source file are marked as required.
+`
+
+/*
+initCommand drafts .env.yaml from .env files given as arguments, .env by default. The schema is
+written to the file set by -o, or to stdout if it is "-". An existing file is overwritten only
+with -force.
+*/
+func initCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	output := fs.String("o", defaultSchemaFile, `file to write the schema to, "-" for stdout`)
+	force := fs.Bool("force", false, "overwrite the output file if it exists")
+	if code, ok := parseFlagsWithArgs(fs, args, stderr); !ok {
+		return code
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{".env"}
+	}
+
+	var entries [][]internal.DotenvEntry
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return report(stderr, fs.Name(), exitEnvFile, err)
+		}
+
+		fileEntries, err := internal.ParseDotenv(f, string(data))
+		if err != nil {
+			return report(stderr, fs.Name(), exitEnvFile, err)
+		}
+		entries = append(entries, fileEntries)
+	}
+
+	data, err := marshalSchema(internal.InferSchema(entries))
+	if err != nil {
+		return report(stderr, fs.Name(), exitSchema, err)
+	}
+	data = append([]byte(initHeader), data...)
+
+	if *output == "-" {
+		_, _ = stdout.Write(data)
+		return exitOK
+	}
+
+	if _, err := os.Stat(*output); err == nil && !*force {
+		err := fmt.Errorf("%s already exists, use -force to overwrite it", *output)
+		return report(stderr, fs.Name(), exitUsage, err)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return report(stderr, fs.Name(), exitSchema, err)
+	}
+
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		return report(stderr, fs.Name(), exitSchema, err)
+	}
+
+	fmt.Fprintf(stdout, "wrote %s, review it before use\n", *output)
+	return exitOK
+}
+
+// marshalSchema encodes config as YAML with the indentation used in .env.yaml examples
+func marshalSchema(config internal.Config) ([]byte, error) {
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestInitCommand(t *testing.T) {
+	files := map[string]string{
+		".env":       "# HTTP port\nPORT=8080\nDEBUG=true\nAPI_URL=https://api.local",
+		"prod.env":   "PORT=80\nAPI_URL=https://api.example.com",
+		"broken.env": "PORT",
+	}
+
+	t.Run("Schema is written to .env.yaml", func(t *testing.T) {
+		createFiles(t, files)
+
+		code, stdout, stderr := runCLI(t, "init", ".env", "prod.env")
+		if code != exitOK {
+			t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+		}
+		if !strings.Contains(stdout, "wrote .env.yaml") {
+			t.Errorf("unexpected output \"%s\"", stdout)
+		}
+
+		config, err := internal.GetConfig(".env.yaml")
+		if err != nil {
+			t.Fatalf("generated schema can not be read: %v", err)
+		}
+
+		expected := []internal.VarEntry{
+			{Name: "PORT", Required: true, Type: internal.IntType, Description: "HTTP port"},
+			{Name: "DEBUG", Type: internal.BoolType},
+			{Name: "API_URL", Required: true, Type: internal.StringType},
+		}
+		if len(config.Vars) != len(expected) {
+			t.Fatalf("expected %d vars, got %+v", len(expected), config.Vars)
+		}
+		for i, v := range config.Vars {
+			if v.Name != expected[i].Name || v.Required != expected[i].Required ||
+				v.Type != expected[i].Type || v.Description != expected[i].Description {
+				t.Errorf("expected var %+v, got %+v", expected[i], v)
+			}
+		}
+		if config.Vars[2].Pattern == nil {
+			t.Error("expected API_URL to get a pattern")
+		}
+
+		code, _, stderr = runCLI(t, "init")
+		if code != exitUsage || !strings.Contains(stderr, "already exists") {
+			t.Errorf("expected existing .env.yaml not to be overwritten, got %d: %s", code, stderr)
+		}
+
+		code, _, stderr = runCLI(t, "init", "-force")
+		if code != exitOK {
+			t.Errorf("expected -force to overwrite .env.yaml, got %d: %s", code, stderr)
+		}
+	})
+
+	t.Run("Schema is written to stdout", func(t *testing.T) {
+		createFiles(t, files)
+
+		code, stdout, stderr := runCLI(t, "init", "-o", "-")
+		if code != exitOK {
+			t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr)
+		}
+		if !strings.Contains(stdout, "- name: PORT\n") {
+			t.Errorf("unexpected output \"%s\"", stdout)
+		}
+		if _, err := os.Stat(".env.yaml"); err == nil {
+			t.Error(".env.yaml must not be created")
+		}
+	})
+
+	t.Run("Broken .env file", func(t *testing.T) {
+		createFiles(t, files)
+
+		code, _, stderr := runCLI(t, "init", "broken.env")
+		if code != exitEnvFile {
+			t.Errorf("expected exit code %d, got %d: %s", exitEnvFile, code, stderr)
+		}
+	})
+}
@@ -0,0 +1,248 @@
+/*
+Command envcheck checks environment variables against a .env.yaml schema without running the
+application.
+
+Usage:
+
+	envcheck <command> [flags]
+
+Commands:
+
+	validate  check .env files or the process environment against the schema
+	run       validate the environment and run a command with it
+	init      draft .env.yaml from existing .env files
+	example   render .env.example from the schema or check that it is up to date
+	docs      render documentation of the vars as Markdown or HTML
+	export    print the validated environment in the format of another tool
+
+Exit codes:
+
+	0  success
+	1  the environment does not pass the schema
+	2  invalid command line
+	3  the schema can not be read, written or is invalid
+	4  a .env file can not be read or parsed
+	5  the command given to run can not be started
+	6  the output file can not be written
+
+Once the command given to run is started, its exit code is returned.
+*/
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+const (
+	exitOK      = 0
+	exitInvalid = 1
+	exitUsage   = 2
+	exitSchema  = 3
+	exitEnvFile = 4
+	exitCommand = 5
+	exitOutput  = 6
+)
+
+const defaultSchemaFile = ".env.yaml"
+
+// command runs a subcommand with its arguments and returns the exit code
+type command func(args []string, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"validate": validateCommand,
+	"run":      runCommand,
+	"init":     initCommand,
+	"example":  exampleCommand,
+	"docs":     docsCommand,
+	"export":   exportCommand,
+}
+
+func main() {
+	os.Exit(cli(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func cli(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		printUsage(stdout)
+		return exitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "envcheck: unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	return cmd(args[1:], stdout, stderr)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage: envcheck <command> [flags]
+
+Commands:
+  validate  check .env files or the process environment against the schema
+  run       validate the environment and run a command with it
+  init      draft .env.yaml from existing .env files
+  example   render .env.example from the schema or check that it is up to date
+  docs      render documentation of the vars as Markdown or HTML
+  export    print the validated environment in the format of another tool
+
+Run "envcheck <command> -h" for flags of a command.
+`)
+}
+
+// parseFlags parses args of subcommand fs, exit code is returned if the command must stop
+func parseFlags(fs *flag.FlagSet, args []string, stderr io.Writer) (int, bool) {
+	if code, ok := parseFlagsWithArgs(fs, args, stderr); !ok {
+		return code, false
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "envcheck %s: unexpected arguments %v\n", fs.Name(), fs.Args())
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+// parseFlagsWithArgs is parseFlags for subcommands which take arguments after the flags
+func parseFlagsWithArgs(fs *flag.FlagSet, args []string, stderr io.Writer) (int, bool) {
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+// report prints err of subcommand name and returns code
+func report(stderr io.Writer, name string, code int, err error) int {
+	fmt.Fprintf(stderr, "envcheck %s: %v\n", name, err)
+	return code
+}
+
+// stringList is a flag which may be repeated
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+/*
+readSchema reads schema from path. If path is empty, .env.yaml is read from the project root of
+the working directory.
+*/
+func readSchema(path string) (internal.Config, error) {
+	if path == "" {
+		root, err := projectRoot()
+		if err != nil {
+			return internal.Config{}, err
+		}
+		path = filepath.Join(root, defaultSchemaFile)
+	}
+	return internal.ReadConfig(os.ReadFile, path)
+}
+
+func projectRoot() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", errors.Join(errors.New("failed to get current working directory"), err)
+	}
+	return internal.FindProjectRoot(cwd)
+}
+
+// processEnv returns a snapshot of the process environment
+func processEnv() (map[string]string, error) {
+	return internal.ParseEnviron(os.Environ())
+}
+
+/*
+loadDotenv loads files into env and records sources of their vars. If files is empty, the .env
+cascade is loaded from the project root instead. Unlike in envcheck.Load, .env of the cascade is
+optional, so the process environment alone is enough, e.g. in a container.
+*/
+func loadDotenv(
+	env map[string]string,
+	files []string,
+	sources map[string]internal.VarSource,
+) error {
+	o := internal.DotenvOptions{
+		Selector:     internal.DefaultEnvSelector,
+		OptionalBase: true,
+		Env:          env,
+		Sources:      sources,
+	}
+
+	if len(files) == 0 {
+		root, err := projectRoot()
+		if err != nil {
+			return err
+		}
+		o.Dir = root
+	}
+	for _, f := range files {
+		o.Files = append(o.Files, internal.DotenvFile{Path: f})
+	}
+
+	_, err := internal.LoadDotenv(o)
+	return err
+}
+
+/*
+resolveEnv loads .env files on top of the process environment like envcheck.Load does, applies
+defaults and validates the result against c. If it fails, the exit code is returned with the error.
+*/
+func resolveEnv(
+	c *internal.Config,
+	files []string,
+	failFast bool,
+) (map[string]string, int, error) {
+	env, err := processEnv()
+	if err != nil {
+		return nil, exitEnvFile, err
+	}
+	sources := make(map[string]internal.VarSource)
+	if err := loadDotenv(env, files, sources); err != nil {
+		return nil, exitEnvFile, err
+	}
+
+	resolved, err := internal.Validate(c, env, internal.ValidateOptions{
+		FailFast: failFast,
+		Sources:  sources,
+	})
+	if err != nil {
+		return nil, validationExitCode(err), err
+	}
+	return resolved, exitOK, nil
+}
+
+/*
+validationExitCode returns exitSchema if validation failed because of an invalid schema, which
+CheckSchema normally finds before, and exitInvalid if the environment does not pass the schema.
+*/
+func validationExitCode(err error) int {
+	if errors.As(err, new(*internal.SchemaError)) {
+		return exitSchema
+	}
+	return exitInvalid
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+// runCLI runs envcheck with args and returns its exit code, stdout and stderr
+func runCLI(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := cli(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// createFiles creates files in a temporary directory and makes it the working directory
+func createFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	tempdir := t.TempDir()
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(tempdir, name), []byte(content), 0o644)
+		if err != nil {
+			t.Fatalf("failed to create %s file: %v", name, err)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(tempdir); err != nil {
+		t.Fatalf("failed to chdir to %s", tempdir)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+
+	return tempdir
+}
+
+func TestCLI(t *testing.T) {
+	testCases := []struct {
+		name           string
+		args           []string
+		expectedCode   int
+		expectedOutput string
+	}{
+		{name: "No command", args: nil, expectedCode: exitUsage, expectedOutput: "Usage:"},
+		{name: "Help", args: []string{"help"}, expectedCode: exitOK, expectedOutput: "Usage:"},
+		{
+			name:           "Unknown command",
+			args:           []string{"frobnicate"},
+			expectedCode:   exitUsage,
+			expectedOutput: "unknown command \"frobnicate\"",
+		},
+		{
+			name:           "Unknown flag",
+			args:           []string{"validate", "-frobnicate"},
+			expectedCode:   exitUsage,
+			expectedOutput: "flag provided but not defined",
+		},
+		{
+			name:           "Unexpected argument",
+			args:           []string{"validate", "extra"},
+			expectedCode:   exitUsage,
+			expectedOutput: "unexpected arguments [extra]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, tc.args...)
+			if code != tc.expectedCode {
+				t.Errorf("expected exit code %d, got %d", tc.expectedCode, code)
+			}
+			if !strings.Contains(stdout+stderr, tc.expectedOutput) {
+				t.Errorf("expected output to contain \"%s\", got \"%s%s\"",
+					tc.expectedOutput, stdout, stderr)
+			}
+		})
+	}
+}
+
+func TestValidationExitCode(t *testing.T) {
+	schemaErr := &internal.SchemaError{Message: "var PORT has an unsupported type"}
+	err := errors.Join(errors.New("1 of 1 vars failed validation"), schemaErr)
+	if code := validationExitCode(err); code != exitSchema {
+		t.Errorf("expected exit code %d for schema error, got %d", exitSchema, code)
+	}
+
+	typeErr := &internal.TypeError{Type: internal.IntType}
+	if code := validationExitCode(typeErr); code != exitInvalid {
+		t.Errorf("expected exit code %d for type error, got %d", exitInvalid, code)
+	}
+}
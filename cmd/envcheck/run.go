@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+)
+
+/*
+runCommand loads the .env cascade on top of the process environment, applies defaults and
+validates the result like envcheck.Load does. If it passes, the command after the flags is started
+with the resolved environment:
+
+	envcheck run -- ./server --flag
+
+Signals received by envcheck are forwarded to the command, and its exit code is returned, so
+envcheck can be used as the entrypoint of a container.
+*/
+func runCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	schemaPath := fs.String("schema", "",
+		"path to the schema (default .env.yaml in the project root)")
+	var envFiles stringList
+	fs.Var(&envFiles, "env-file",
+		".env file to load instead of the .env cascade, may be repeated")
+	failFast := fs.Bool("fail-fast", false,
+		"stop at the first failing stage instead of reporting all problems")
+	if code, ok := parseFlagsWithArgs(fs, args, stderr); !ok {
+		return code
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "envcheck run: command is not set")
+		fmt.Fprintln(stderr, "usage: envcheck run [flags] -- command [args]")
+		return exitUsage
+	}
+
+	config, err := readSchema(*schemaPath)
+	if err != nil {
+		return report(stderr, fs.Name(), exitSchema, err)
+	}
+
+	resolved, code, err := resolveEnv(&config, envFiles, *failFast)
+	if err != nil {
+		return report(stderr, fs.Name(), code, err)
+	}
+
+	cmd := exec.Command(fs.Arg(0), fs.Args()[1:]...)
+	cmd.Env = environ(resolved)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	return runChild(cmd, stderr)
+}
+
+// runChild runs cmd, forwarding signals to it, and returns its exit code
+func runChild(cmd *exec.Cmd, stderr io.Writer) int {
+	// Start listening before the start, so no signal is lost in between
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, forwardedSignals...)
+	defer func() {
+		signal.Stop(signals)
+		close(signals)
+	}()
+
+	if err := cmd.Start(); err != nil {
+		return report(stderr, "run", exitCommand, err)
+	}
+
+	go func() {
+		for sig := range signals {
+			// The command may have exited already, then there is nobody to notify
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+
+	err := cmd.Wait()
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		return exitCode(exitError.ProcessState)
+	}
+	if err != nil {
+		return report(stderr, "run", exitCommand, err)
+	}
+	return exitOK
+}
+
+// environ converts env to "KEY=value" strings sorted by key
+func environ(env map[string]string) []string {
+	result := make([]string, 0, len(env))
+	for key, value := range env {
+		result = append(result, key+"="+value)
+	}
+	slices.Sort(result)
+	return result
+}
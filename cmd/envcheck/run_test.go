@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+/*
+TestHelperProcess is not a real test. It is started by run tests as the child command: it prints
+vars named in its arguments and exits with the code from ENVCHECK_HELPER_EXIT. With
+ENVCHECK_HELPER_WAIT it exits only after a signal is received.
+*/
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("ENVCHECK_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	if len(args) > 0 {
+		args = args[1:]
+	}
+	for _, key := range args {
+		value, isSet := os.LookupEnv(key)
+		fmt.Printf("%s=%s %v\n", key, value, isSet)
+	}
+
+	code, _ := strconv.Atoi(os.Getenv("ENVCHECK_HELPER_EXIT"))
+	if os.Getenv("ENVCHECK_HELPER_WAIT") == "1" {
+		waitForSignal()
+	}
+	os.Exit(code)
+}
+
+func TestRunCommand(t *testing.T) {
+	const schema = `
+vars:
+  - name: ENVCHECK_TEST_PORT
+    required: true
+    type: int
+  - name: ENVCHECK_TEST_NAME
+    type: string
+    default_value: service
+`
+	helper := []string{os.Args[0], "-test.run=^TestHelperProcess$", "--"}
+
+	testCases := []struct {
+		name           string
+		files          map[string]string
+		env            map[string]string
+		args           []string
+		exitCode       string
+		expectedCode   int
+		expectedOutput []string
+	}{
+		{
+			name:  "Command gets resolved environment",
+			files: map[string]string{".env.yaml": schema, ".env": "ENVCHECK_TEST_PORT=80"},
+			args: slices.Concat(
+				helper,
+				[]string{"ENVCHECK_TEST_PORT", "ENVCHECK_TEST_NAME"},
+			),
+			expectedCode: exitOK,
+			expectedOutput: []string{
+				"ENVCHECK_TEST_PORT=80 true",
+				"ENVCHECK_TEST_NAME=service true",
+			},
+		},
+		{
+			name:           "Exit code of command is returned",
+			files:          map[string]string{".env.yaml": schema, ".env": "ENVCHECK_TEST_PORT=80"},
+			args:           slices.Concat(helper, []string{"ENVCHECK_TEST_PORT"}),
+			exitCode:       "42",
+			expectedCode:   42,
+			expectedOutput: []string{"ENVCHECK_TEST_PORT=80 true"},
+		},
+		{
+			name: "Env files instead of cascade",
+			files: map[string]string{
+				".env.yaml": schema,
+				"app.env":   "ENVCHECK_TEST_PORT=81",
+			},
+			args: slices.Concat(
+				[]string{"-env-file", "app.env", "--"},
+				helper,
+				[]string{"ENVCHECK_TEST_PORT"},
+			),
+			expectedCode:   exitOK,
+			expectedOutput: []string{"ENVCHECK_TEST_PORT=81 true"},
+		},
+		{
+			name:           "Process environment without .env",
+			files:          map[string]string{".env.yaml": schema},
+			env:            map[string]string{"ENVCHECK_TEST_PORT": "82"},
+			args:           slices.Concat(helper, []string{"ENVCHECK_TEST_PORT"}),
+			expectedCode:   exitOK,
+			expectedOutput: []string{"ENVCHECK_TEST_PORT=82 true"},
+		},
+		{
+			name: "Invalid environment",
+			files: map[string]string{
+				".env.yaml": schema,
+				".env":      "ENVCHECK_TEST_PORT=http",
+			},
+			args:           slices.Concat(helper, []string{"ENVCHECK_TEST_PORT"}),
+			expectedCode:   exitInvalid,
+			expectedOutput: []string{"var ENVCHECK_TEST_PORT is not a valid int"},
+		},
+		{
+			name:           "Command is not set",
+			files:          map[string]string{".env.yaml": schema, ".env": ""},
+			args:           []string{"--"},
+			expectedCode:   exitUsage,
+			expectedOutput: []string{"command is not set"},
+		},
+		{
+			name:           "Command can not be started",
+			files:          map[string]string{".env.yaml": schema, ".env": "ENVCHECK_TEST_PORT=80"},
+			args:           []string{"./does-not-exist"},
+			expectedCode:   exitCommand,
+			expectedOutput: []string{"does-not-exist"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			createFiles(t, tc.files)
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+			t.Setenv("ENVCHECK_HELPER_PROCESS", "1")
+			t.Setenv("ENVCHECK_HELPER_EXIT", tc.exitCode)
+
+			code, stdout, stderr := runCLI(t, append([]string{"run"}, tc.args...)...)
+			if code != tc.expectedCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedCode, code, stderr)
+			}
+			for _, expectedOutput := range tc.expectedOutput {
+				if !strings.Contains(stdout+stderr, expectedOutput) {
+					t.Errorf("expected output to contain \"%s\", got \"%s%s\"",
+						expectedOutput, stdout, stderr)
+				}
+			}
+		})
+	}
+}
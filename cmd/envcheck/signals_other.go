@@ -0,0 +1,15 @@
+//go:build !unix
+
+package main
+
+import (
+	"os"
+)
+
+// Signals which are forwarded to the command started by run
+var forwardedSignals = []os.Signal{os.Interrupt}
+
+// exitCode returns exit code of a finished process
+func exitCode(state *os.ProcessState) int {
+	return state.ExitCode()
+}
@@ -0,0 +1,56 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func waitForSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM)
+	<-signals
+}
+
+func TestRunForwardsSignals(t *testing.T) {
+	createFiles(t, map[string]string{".env.yaml": "vars: []", ".env": ""})
+	t.Setenv("ENVCHECK_HELPER_PROCESS", "1")
+	t.Setenv("ENVCHECK_HELPER_WAIT", "1")
+	t.Setenv("ENVCHECK_HELPER_EXIT", "7")
+
+	// SIGTERM must not kill the test itself when run is not listening
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGTERM)
+	defer signal.Stop(guard)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		// The command may not listen yet, so the signal is repeated until run returns
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+			}
+		}
+	}()
+
+	code, _, stderr := runCLI(t, "run", "--", os.Args[0], "-test.run=^TestHelperProcess$")
+	close(done)
+	<-stopped
+
+	// 7 if the command handled the signal, 128+SIGTERM if it was killed before it started listening
+	if code != 7 && code != 128+int(syscall.SIGTERM) {
+		t.Errorf("expected exit code of signaled command, got %d: %s", code, stderr)
+	}
+}
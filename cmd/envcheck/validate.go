@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+/*
+validateCommand checks .env files given with -env-file against the schema. Without them the
+process environment is checked, which suits container start-up where the environment is set by
+the orchestrator.
+*/
+func validateCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	schemaPath := fs.String("schema", "",
+		"path to the schema (default .env.yaml in the project root)")
+	var envFiles stringList
+	fs.Var(&envFiles, "env-file",
+		".env file to check, may be repeated (default the process environment)")
+	withProcessEnv := fs.Bool("process-env", false,
+		"check -env-file files on top of the process environment")
+	strict := fs.Bool("strict", false,
+		"fail on vars set in -env-file files but not declared in the schema")
+	failFast := fs.Bool("fail-fast", false,
+		"stop at the first failing stage instead of reporting all problems")
+	if code, ok := parseFlags(fs, args, stderr); !ok {
+		return code
+	}
+
+	config, err := readSchema(*schemaPath)
+	if err != nil {
+		return report(stderr, fs.Name(), exitSchema, err)
+	}
+
+	env := make(map[string]string)
+	if len(envFiles) == 0 || *withProcessEnv {
+		env, err = processEnv()
+		if err != nil {
+			return report(stderr, fs.Name(), exitEnvFile, err)
+		}
+	}
+
+	sources := make(map[string]internal.VarSource)
+	if len(envFiles) > 0 {
+		if err := loadDotenv(env, envFiles, sources); err != nil {
+			return report(stderr, fs.Name(), exitEnvFile, err)
+		}
+	}
+
+	var problems []error
+	if *strict {
+		problems, err = undeclaredVars(&config, envFiles)
+		if err != nil {
+			return report(stderr, fs.Name(), exitEnvFile, err)
+		}
+	}
+
+	_, err = internal.Validate(&config, env, internal.ValidateOptions{
+		FailFast: *failFast,
+		Sources:  sources,
+	})
+	if err := errors.Join(append(problems, err)...); err != nil {
+		return report(stderr, fs.Name(), validationExitCode(err), err)
+	}
+
+	fmt.Fprintf(stdout, "%d vars are valid\n", len(config.Vars))
+	return exitOK
+}
+
+// undeclaredVars returns a problem for every var which is set in files but not declared in c
+func undeclaredVars(c *internal.Config, files []string) ([]error, error) {
+	declared := make(map[string]bool, len(c.Vars))
+	for _, v := range c.Vars {
+		declared[v.Name] = true
+	}
+
+	var problems []error
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to read %s", f), err)
+		}
+
+		entries, err := internal.ParseDotenv(f, string(data))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if !declared[entry.Key] {
+				problems = append(problems, fmt.Errorf(
+					"%s:%d: var %s is not declared in schema",
+					entry.File,
+					entry.Line,
+					entry.Key,
+				))
+			}
+		}
+	}
+
+	return problems, nil
+}
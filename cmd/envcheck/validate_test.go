@@ -0,0 +1,172 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand(t *testing.T) {
+	const schema = `
+vars:
+  - name: ENVCHECK_TEST_PORT
+    required: true
+    type: int
+  - name: ENVCHECK_TEST_NAME
+    type: string
+    pattern: ^[a-z]+$
+`
+
+	testCases := []struct {
+		name           string
+		files          map[string]string
+		processEnv     map[string]string
+		args           []string
+		expectedCode   int
+		expectedOutput string
+	}{
+		{
+			name: "Valid .env file",
+			files: map[string]string{
+				".env.yaml": schema,
+				"app.env":   "ENVCHECK_TEST_PORT=80",
+			},
+			args:           []string{"-env-file", "app.env"},
+			expectedCode:   exitOK,
+			expectedOutput: "2 vars are valid",
+		},
+		{
+			name: "Invalid .env file",
+			files: map[string]string{
+				".env.yaml": schema,
+				"app.env":   "ENVCHECK_TEST_PORT=http\nENVCHECK_TEST_NAME=X",
+			},
+			args:           []string{"-env-file", "app.env"},
+			expectedCode:   exitInvalid,
+			expectedOutput: "var ENVCHECK_TEST_PORT is not a valid int (app.env:1)",
+		},
+		{
+			name:           "Valid process environment",
+			files:          map[string]string{".env.yaml": schema},
+			processEnv:     map[string]string{"ENVCHECK_TEST_PORT": "80"},
+			expectedCode:   exitOK,
+			expectedOutput: "2 vars are valid",
+		},
+		{
+			name: "Process environment is not checked with -env-file",
+			files: map[string]string{
+				".env.yaml": schema,
+				"app.env":   "ENVCHECK_TEST_NAME=x",
+			},
+			processEnv:     map[string]string{"ENVCHECK_TEST_PORT": "80"},
+			args:           []string{"-env-file", "app.env"},
+			expectedCode:   exitInvalid,
+			expectedOutput: "required var ENVCHECK_TEST_PORT is not set",
+		},
+		{
+			name: "Process environment under .env file",
+			files: map[string]string{
+				".env.yaml": schema,
+				"app.env":   "ENVCHECK_TEST_NAME=x",
+			},
+			processEnv:     map[string]string{"ENVCHECK_TEST_PORT": "80"},
+			args:           []string{"-env-file", "app.env", "-process-env"},
+			expectedCode:   exitOK,
+			expectedOutput: "2 vars are valid",
+		},
+		{
+			name: "Strict mode",
+			files: map[string]string{
+				".env.yaml": schema,
+				"app.env":   "ENVCHECK_TEST_PORT=80\nEXTRA=1",
+			},
+			args:           []string{"-env-file", "app.env", "-strict"},
+			expectedCode:   exitInvalid,
+			expectedOutput: "app.env:2: var EXTRA is not declared in schema",
+		},
+		{
+			name: "Schema from flag",
+			files: map[string]string{
+				"schema.yaml": schema,
+				"app.env":     "ENVCHECK_TEST_PORT=80",
+			},
+			args:           []string{"-schema", "schema.yaml", "-env-file", "app.env"},
+			expectedCode:   exitOK,
+			expectedOutput: "2 vars are valid",
+		},
+		{
+			name:           "Missing schema",
+			files:          map[string]string{"app.env": "ENVCHECK_TEST_PORT=80"},
+			args:           []string{"-env-file", "app.env"},
+			expectedCode:   exitSchema,
+			expectedOutput: "failed to read .env.yaml",
+		},
+		{
+			name:           "Invalid schema",
+			files:          map[string]string{".env.yaml": "vars: [", "app.env": ""},
+			args:           []string{"-env-file", "app.env"},
+			expectedCode:   exitSchema,
+			expectedOutput: "failed to unmarshal .env.yaml",
+		},
+		{
+			name: "Unsupported type",
+			files: map[string]string{
+				".env.yaml": "vars:\n  - name: ENVCHECK_TEST_PORT\n    type: bogus\n",
+				"app.env":   "ENVCHECK_TEST_PORT=80",
+			},
+			args:           []string{"-env-file", "app.env"},
+			expectedCode:   exitSchema,
+			expectedOutput: "var ENVCHECK_TEST_PORT has an unsupported type \"bogus\"",
+		},
+		{
+			name: "Invalid options of unset var",
+			files: map[string]string{
+				".env.yaml": "vars:\n  - name: ENVCHECK_TEST_PORT\n    type: int\n    min: 1s\n",
+			},
+			expectedCode:   exitSchema,
+			expectedOutput: "var ENVCHECK_TEST_PORT has min or max, but its type is int",
+		},
+		{
+			name: "Pattern which does not compile",
+			files: map[string]string{
+				".env.yaml": "vars:\n  - name: ENVCHECK_TEST_NAME\n    type: string\n" +
+					"    pattern: \"(\"\n",
+				"app.env": "ENVCHECK_TEST_NAME=x",
+			},
+			args:           []string{"-env-file", "app.env"},
+			expectedCode:   exitSchema,
+			expectedOutput: "failed to compile regex for variable ENVCHECK_TEST_NAME",
+		},
+		{
+			name:           "Broken .env file",
+			files:          map[string]string{".env.yaml": schema, "app.env": "ENVCHECK_TEST_PORT"},
+			args:           []string{"-env-file", "app.env"},
+			expectedCode:   exitEnvFile,
+			expectedOutput: "invalid .env file app.env",
+		},
+		{
+			name:           "Missing .env file",
+			files:          map[string]string{".env.yaml": schema},
+			args:           []string{"-env-file", "missing.env"},
+			expectedCode:   exitEnvFile,
+			expectedOutput: "failed to read .env",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			createFiles(t, tc.files)
+			for key, value := range tc.processEnv {
+				t.Setenv(key, value)
+			}
+
+			code, stdout, stderr := runCLI(t, append([]string{"validate"}, tc.args...)...)
+			if code != tc.expectedCode {
+				t.Errorf("expected exit code %d, got %d: %s", tc.expectedCode, code, stderr)
+			}
+			if !strings.Contains(stdout+stderr, tc.expectedOutput) {
+				t.Errorf("expected output to contain \"%s\", got \"%s%s\"",
+					tc.expectedOutput, stdout, stderr)
+			}
+		})
+	}
+}
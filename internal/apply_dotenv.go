@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Precedence decides which value is used when a var is set both in the process and in a .env file
+type Precedence int
+
+const (
+	// Value already set in the process environment is kept
+	ProcessEnvWins Precedence = iota
+	// Value from .env file overwrites the process environment
+	FileWins
+	// Var set in both places with different values is an error
+	ErrorOnConflict
+)
+
+func (p Precedence) String() string {
+	switch p {
+	case ProcessEnvWins:
+		return "process env wins"
+	case FileWins:
+		return "file wins"
+	case ErrorOnConflict:
+		return "error on conflict"
+	default:
+		return fmt.Sprintf("Precedence(%d)", int(p))
+	}
+}
+
+/*
+ShadowedVar describes a var which is set both in the process environment and in a .env file with
+different values. Which of the values is hidden depends on Precedence.
+*/
+type ShadowedVar struct {
+	Key          string
+	ProcessValue string
+	FileValue    string
+	// Location of the definition in .env file
+	File string
+	Line int
+}
+
+/*
+This function sets vars from entries in env, a snapshot of the process environment, according to
+precedence p. If a key is defined several times, the last definition is used. Vars which are
+already set in env with a different value are returned as shadowed. With ErrorOnConflict such
+vars are not set and an error is returned for each of them.
+*/
+func ApplyDotenv(
+	entries []DotenvEntry,
+	env map[string]string,
+	p Precedence,
+) ([]ShadowedVar, error) {
+	var applyError error = nil
+	var shadowed []ShadowedVar
+
+	for _, entry := range lastDefinitions(entries) {
+		processValue, isSet := env[entry.Key]
+		if isSet && processValue == entry.Value {
+			continue
+		}
+
+		if isSet {
+			shadowed = append(shadowed, ShadowedVar{
+				Key:          entry.Key,
+				ProcessValue: processValue,
+				FileValue:    entry.Value,
+				File:         entry.File,
+				Line:         entry.Line,
+			})
+
+			if p == ProcessEnvWins {
+				continue
+			}
+			if p == ErrorOnConflict {
+				applyError = errors.Join(
+					applyError,
+					fmt.Errorf(
+						"%s: var %s is already set in the process environment with another value",
+						entryLocation(entry),
+						entry.Key,
+					),
+				)
+				continue
+			}
+		}
+
+		env[entry.Key] = entry.Value
+	}
+
+	return shadowed, applyError
+}
+
+// VarSource is the location of the .env definition which a var value came from
+type VarSource struct {
+	File string
+	Line int
+}
+
+// DotenvSources returns sources of vars whose values in env came from entries
+func DotenvSources(entries []DotenvEntry, env map[string]string) map[string]VarSource {
+	sources := make(map[string]VarSource)
+	for _, entry := range lastDefinitions(entries) {
+		if value, isSet := env[entry.Key]; isSet && value == entry.Value {
+			sources[entry.Key] = VarSource{File: entry.File, Line: entry.Line}
+		}
+	}
+	return sources
+}
+
+// lastDefinitions keeps only the last entry for every key, in order of the first appearance
+func lastDefinitions(entries []DotenvEntry) []DotenvEntry {
+	index := make(map[string]int)
+	var result []DotenvEntry
+
+	for _, entry := range entries {
+		if i, ok := index[entry.Key]; ok {
+			result[i] = entry
+			continue
+		}
+		index[entry.Key] = len(result)
+		result = append(result, entry)
+	}
+
+	return result
+}
@@ -0,0 +1,81 @@
+package internal_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestApplyDotenv(t *testing.T) {
+	entries := []internal.DotenvEntry{
+		{Key: "PORT", Value: "3000", File: ".env", Line: 1},
+		{Key: "HOST", Value: "localhost", File: ".env", Line: 2},
+		{Key: "SAME", Value: "same", File: ".env", Line: 3},
+		{Key: "HOST", Value: "127.0.0.1", File: ".env", Line: 4},
+	}
+
+	expectedShadowed := []internal.ShadowedVar{
+		{Key: "PORT", ProcessValue: "8080", FileValue: "3000", File: ".env", Line: 1},
+	}
+
+	testCases := []struct {
+		precedence        internal.Precedence
+		expectedVars      map[string]string
+		expectedErrorText string
+	}{
+		{
+			precedence: internal.ProcessEnvWins,
+			expectedVars: map[string]string{
+				"PORT": "8080",
+				"HOST": "127.0.0.1",
+				"SAME": "same",
+			},
+		},
+		{
+			precedence: internal.FileWins,
+			expectedVars: map[string]string{
+				"PORT": "3000",
+				"HOST": "127.0.0.1",
+				"SAME": "same",
+			},
+		},
+		{
+			precedence: internal.ErrorOnConflict,
+			expectedVars: map[string]string{
+				"PORT": "8080",
+				"HOST": "127.0.0.1",
+				"SAME": "same",
+			},
+			expectedErrorText: ".env:1: var PORT is already set in the process environment",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.precedence.String(), func(t *testing.T) {
+			env := map[string]string{"PORT": "8080", "SAME": "same"}
+
+			shadowed, err := internal.ApplyDotenv(entries, env, tc.precedence)
+			if tc.expectedErrorText == "" && err != nil {
+				t.Fatalf("unexpected error \"%v\"", err)
+			}
+			if tc.expectedErrorText != "" {
+				if err == nil {
+					t.Fatal("expected to get an error")
+				}
+				if !strings.Contains(err.Error(), tc.expectedErrorText) {
+					t.Fatalf("expected \"%s\" to contain \"%s\"", err.Error(), tc.expectedErrorText)
+				}
+			}
+
+			if !reflect.DeepEqual(shadowed, expectedShadowed) {
+				t.Errorf("expected shadowed vars: %v\n Actual: %v", expectedShadowed, shadowed)
+			}
+
+			if !reflect.DeepEqual(env, tc.expectedVars) {
+				t.Errorf("expected vars: %v\n Actual: %v", tc.expectedVars, env)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+/*
+checkBoundOptions makes sure that min and max are only set for the types which have bounds, and
+that they are valid values of the var with min not greater than max.
+*/
+func checkBoundOptions(v VarEntry) error {
+	if v.Min == "" && v.Max == "" {
+		return nil
+	}
+
+	var message string
+	switch v.Type {
+	case DurationType:
+		message = checkBoundValues(v, time.ParseDuration)
+	case SizeType:
+		message = checkBoundValues(v, parseByteSize)
+	case QuantityType:
+		message = checkBoundValues(v, func(bound string) (float64, error) {
+			return parseQuantity(v, bound, false)
+		})
+	default:
+		message = fmt.Sprintf("var %s has min or max, but its type is %s", v.Name, v.Type)
+	}
+	if message == "" {
+		return nil
+	}
+
+	return &SchemaError{
+		ErrorDetails: ErrorDetails{Var: v.Name, Rule: "type"},
+		Message:      message,
+	}
+}
+
+// checkBoundValues returns what is wrong with min and max of var v, "" if nothing
+func checkBoundValues[T cmp.Ordered](v VarEntry, parse func(string) (T, error)) string {
+	var lower, upper T
+	var err error
+
+	if v.Min != "" {
+		if lower, err = parse(v.Min); err != nil {
+			return fmt.Sprintf("var %s has min %q, which is not a valid %s", v.Name, v.Min, v.Type)
+		}
+	}
+	if v.Max != "" {
+		if upper, err = parse(v.Max); err != nil {
+			return fmt.Sprintf("var %s has max %q, which is not a valid %s", v.Name, v.Max, v.Type)
+		}
+	}
+	if v.Min != "" && v.Max != "" && lower > upper {
+		return fmt.Sprintf("var %s has min %s greater than max %s", v.Name, v.Min, v.Max)
+	}
+	return ""
+}
+
+/*
+checkBounds returns why value of var v is out of its min and max, "" if it is within them. The
+bounds must have been checked by checkBoundOptions before.
+*/
+func checkBounds[T cmp.Ordered](v VarEntry, value T, parse func(string) (T, error)) string {
+	if v.Min != "" {
+		if lower, _ := parse(v.Min); value < lower {
+			return "must be at least " + boundText(v, v.Min)
+		}
+	}
+	if v.Max != "" {
+		if upper, _ := parse(v.Max); value > upper {
+			return "must be at most " + boundText(v, v.Max)
+		}
+	}
+	return ""
+}
+
+// boundText returns bound as it is shown in errors, with the unit of a quantity var
+func boundText(v VarEntry, bound string) string {
+	if _, err := strconv.ParseFloat(bound, 64); err == nil && v.Type == QuantityType {
+		return bound + v.Unit
+	}
+	return bound
+}
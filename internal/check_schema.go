@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+/*
+CheckSchema makes sure that every var of c can be validated: it has a name and a supported type,
+its options fit the type and its pattern compiles. Elements of list and map vars are checked the
+same way. Vars are checked once, whether they are set or not, and all problems are reported as
+SchemaError values. Vars without a type get type string, like vars built with envcheck.Var.
+*/
+func CheckSchema(c *Config) error {
+	if err := CheckRequiredFields(c); err != nil {
+		return err
+	}
+	for i := range c.Vars {
+		if c.Vars[i].Type == "" {
+			c.Vars[i].Type = StringType
+		}
+	}
+
+	var schemaError error = nil
+	for _, v := range c.Vars {
+		err := checkVarSchema(v)
+		if err == nil && (v.Type == ListType || v.Type == MapType) {
+			err = checkVarSchema(elementEntry(v))
+		}
+		schemaError = errors.Join(schemaError, err)
+	}
+
+	return schemaError
+}
+
+// checkVarSchema returns the first problem of declaration of var v, nil if there are none
+func checkVarSchema(v VarEntry) error {
+	if !slices.Contains(supportedVarTypes, v.Type) {
+		return &SchemaError{
+			ErrorDetails: ErrorDetails{Var: v.Name, Rule: "type"},
+			Message:      fmt.Sprintf("var %s has an unsupported type %q", v.Name, v.Type),
+		}
+	}
+
+	checks := []func(VarEntry) error{
+		checkNetworkOptions,
+		checkTimeOptions,
+		checkQuantityOptions,
+		checkBoundOptions,
+		checkCollectionOptions,
+		checkJSONOptions,
+		checkPatternOptions,
+	}
+	for _, check := range checks {
+		if err := check(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPatternOptions makes sure that pattern is only set for string vars and compiles
+func checkPatternOptions(v VarEntry) error {
+	if v.Pattern == nil {
+		return nil
+	}
+
+	details := ErrorDetails{Var: v.Name, Rule: "pattern"}
+	if v.Type != StringType {
+		return &SchemaError{
+			ErrorDetails: details,
+			Message: fmt.Sprintf(
+				"variable \"%s\" has type %s, \"pattern\" is supported only for type %s",
+				v.Name,
+				v.Type,
+				StringType,
+			),
+		}
+	}
+	if _, err := regexp.Compile(*v.Pattern); err != nil {
+		return &SchemaError{
+			ErrorDetails: details,
+			Message:      fmt.Sprintf("failed to compile regex for variable %s", v.Name),
+			Err:          err,
+		}
+	}
+	return nil
+}
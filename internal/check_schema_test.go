@@ -0,0 +1,247 @@
+package internal_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestCheckSchema(t *testing.T) {
+	pattern := "("
+	digits := "^[0-9]+$"
+
+	testCases := []struct {
+		name              string
+		v                 internal.VarEntry
+		expectedErrorText string
+	}{
+		{
+			name:              "Unsupported type",
+			v:                 internal.VarEntry{Type: "bogus"},
+			expectedErrorText: "var VAR has an unsupported type \"bogus\"",
+		},
+		{
+			name:              "Options of another type",
+			v:                 internal.VarEntry{Type: internal.IntType, Min: "1s"},
+			expectedErrorText: "var VAR has min or max, but its type is int",
+		},
+		{
+			name:              "Pattern which does not compile",
+			v:                 internal.VarEntry{Type: internal.StringType, Pattern: &pattern},
+			expectedErrorText: "failed to compile regex for variable VAR",
+		},
+		{
+			name:              "Pattern of int var",
+			v:                 internal.VarEntry{Type: internal.IntType, Pattern: &digits},
+			expectedErrorText: "\"pattern\" is supported only for type string",
+		},
+		{
+			name: "Invalid element",
+			v: internal.VarEntry{
+				Type:    internal.ListType,
+				Element: &internal.VarEntry{Type: internal.IntType, Pattern: &digits},
+			},
+			expectedErrorText: "\"pattern\" is supported only for type string",
+		},
+		{
+			name: "Element of unsupported type",
+			v: internal.VarEntry{
+				Type:    internal.MapType,
+				Element: &internal.VarEntry{Type: "bogus"},
+			},
+			expectedErrorText: "var VAR has an unsupported type \"bogus\"",
+		},
+		{
+			name:              "URL options of string var",
+			v:                 internal.VarEntry{Type: internal.StringType, RequireHost: true},
+			expectedErrorText: "var VAR has url options, but its type is string",
+		},
+		{
+			name:              "IP version of port var",
+			v:                 internal.VarEntry{Type: internal.PortType, IPVersion: 4},
+			expectedErrorText: "var VAR has ip_version, but its type is port",
+		},
+		{
+			name:              "Invalid IP version",
+			v:                 internal.VarEntry{Type: internal.IPType, IPVersion: 5},
+			expectedErrorText: "var VAR has ip_version 5, expected 4 or 6",
+		},
+		{
+			name:              "Duration bounds of int var",
+			v:                 internal.VarEntry{Type: internal.IntType, Max: "1s"},
+			expectedErrorText: "var VAR has min or max, but its type is int",
+		},
+		{
+			name:              "Layout of string var",
+			v:                 internal.VarEntry{Type: internal.StringType, Layout: "2006"},
+			expectedErrorText: "var VAR has layout, but its type is string",
+		},
+		{
+			name:              "Invalid duration bound",
+			v:                 internal.VarEntry{Type: internal.DurationType, Min: "soon"},
+			expectedErrorText: "var VAR has min \"soon\", which is not a valid duration",
+		},
+		{
+			name:              "Duration min greater than max",
+			v:                 internal.VarEntry{Type: internal.DurationType, Min: "1m", Max: "1s"},
+			expectedErrorText: "var VAR has min 1m greater than max 1s",
+		},
+		{
+			name:              "Units of int var",
+			v:                 internal.VarEntry{Type: internal.IntType, Unit: "%"},
+			expectedErrorText: "var VAR has units, but its type is int",
+		},
+		{
+			name:              "Quantity without unit",
+			v:                 internal.VarEntry{Type: internal.QuantityType},
+			expectedErrorText: "var VAR has type quantity, but its unit is not set",
+		},
+		{
+			name: "Unit which is not positive",
+			v: internal.VarEntry{
+				Type:  internal.QuantityType,
+				Unit:  "s",
+				Units: map[string]float64{"ms": 0},
+			},
+			expectedErrorText: "var VAR has unit \"ms\", which must be a positive number of s",
+		},
+		{
+			name:              "Size min greater than max",
+			v:                 internal.VarEntry{Type: internal.SizeType, Min: "1GB", Max: "1MB"},
+			expectedErrorText: "var VAR has min 1GB greater than max 1MB",
+		},
+		{
+			name: "Invalid quantity bound",
+			v: internal.VarEntry{
+				Type: internal.QuantityType,
+				Unit: "%",
+				Max:  "1kg",
+			},
+			expectedErrorText: "var VAR has max \"1kg\", which is not a valid quantity",
+		},
+		{
+			name:              "Collection options of string var",
+			v:                 internal.VarEntry{Type: internal.StringType, Separator: ";"},
+			expectedErrorText: "var VAR has list or map options, but its type is string",
+		},
+		{
+			name:              "Key value separator of list",
+			v:                 internal.VarEntry{Type: internal.ListType, KeyValueSeparator: ":"},
+			expectedErrorText: "var VAR has key_value_separator, but its type is list",
+		},
+		{
+			name:              "Unique map",
+			v:                 internal.VarEntry{Type: internal.MapType, Unique: true},
+			expectedErrorText: "var VAR has unique, but its type is map",
+		},
+		{
+			name:              "Min items greater than max items",
+			v:                 internal.VarEntry{Type: internal.ListType, MinItems: 3, MaxItems: 2},
+			expectedErrorText: "var VAR has min_items 3 greater than max_items 2",
+		},
+		{
+			name:              "Key value separator containing separator",
+			v:                 internal.VarEntry{Type: internal.MapType, KeyValueSeparator: ","},
+			expectedErrorText: "var VAR has key_value_separator which contains its separator",
+		},
+		{
+			name: "Nested list",
+			v: internal.VarEntry{
+				Type:    internal.ListType,
+				Element: &internal.VarEntry{Type: internal.ListType},
+			},
+			expectedErrorText: "var VAR has element of type list, lists and maps can not be nested",
+		},
+		{
+			name: "Invalid options of element",
+			v: internal.VarEntry{
+				Type:    internal.ListType,
+				Element: &internal.VarEntry{Type: internal.IntType, Max: "1s"},
+			},
+			expectedErrorText: "var VAR has min or max, but its type is int",
+		},
+		{
+			name: "JSON schema of string var",
+			v: internal.VarEntry{
+				Type:       internal.StringType,
+				JSONSchema: &internal.JSONSchema{Type: "object"},
+			},
+			expectedErrorText: "var VAR has json_schema, but its type is string",
+		},
+		{
+			name: "Invalid JSON schema type",
+			v: internal.VarEntry{
+				Type:       internal.JSONType,
+				JSONSchema: &internal.JSONSchema{Type: "dict"},
+			},
+			expectedErrorText: "var VAR has invalid json_schema, " +
+				"type \"dict\" is not one of object",
+		},
+		{
+			name: "Items of JSON schema which is not array",
+			v: internal.VarEntry{
+				Type: internal.JSONType,
+				JSONSchema: &internal.JSONSchema{
+					Properties: map[string]internal.JSONSchema{
+						"list": {Type: "string", Items: &internal.JSONSchema{}},
+					},
+				},
+			},
+			expectedErrorText: "var VAR has invalid json_schema, at /properties/list: " +
+				"items are only allowed for type array",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.v.Name = "VAR"
+			err := internal.CheckSchema(&internal.Config{Vars: []internal.VarEntry{tc.v}})
+
+			var schemaErr *internal.SchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("expected SchemaError, got \"%v\"", err)
+			}
+			if !strings.Contains(err.Error(), tc.expectedErrorText) {
+				t.Errorf("expected \"%s\" to contain \"%s\"", err.Error(), tc.expectedErrorText)
+			}
+		})
+	}
+
+	t.Run("All vars are reported", func(t *testing.T) {
+		config := internal.Config{
+			Vars: []internal.VarEntry{
+				{Name: "FIRST", Type: "bogus"},
+				{Name: "VALID", Type: internal.StringType},
+				{Name: "SECOND", Type: internal.StringType, Pattern: &pattern},
+			},
+		}
+		err := internal.CheckSchema(&config)
+		for _, expected := range []string{"var FIRST", "variable SECOND"} {
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("expected error to contain \"%s\", got \"%v\"", expected, err)
+			}
+		}
+	})
+
+	t.Run("Valid schema", func(t *testing.T) {
+		config := internal.Config{
+			Vars: []internal.VarEntry{
+				{Name: "PORT", Type: internal.PortType},
+				{Name: "NAME", Pattern: &digits},
+				{
+					Name:    "TIMEOUTS",
+					Type:    internal.MapType,
+					Element: &internal.VarEntry{Type: internal.DurationType, Max: "1m"},
+				},
+			},
+		}
+		if err := internal.CheckSchema(&config); err != nil {
+			t.Errorf("unexpected error \"%v\"", err)
+		}
+		if config.Vars[1].Type != internal.StringType {
+			t.Errorf("expected var without type to be string, got %q", config.Vars[1].Type)
+		}
+	})
+}
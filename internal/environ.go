@@ -0,0 +1,120 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LookupEnvFunc returns value of a var and whether it is set, like os.LookupEnv
+type LookupEnvFunc func(key string) (string, bool)
+
+// LookupInMap returns LookupEnvFunc which looks vars up in env
+func LookupInMap(env map[string]string) LookupEnvFunc {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+/*
+This function converts environ in the form of os.Environ(), "KEY=value" strings, to a map.
+If a key is repeated, the last value is used.
+*/
+func ParseEnviron(environ []string) (map[string]string, error) {
+	var parseError error = nil
+	env := make(map[string]string, len(environ))
+
+	for _, kv := range environ {
+		key, value, found := strings.Cut(kv, "=")
+		if found && key == "" {
+			// Windows keeps per-drive working directories in vars like "=C:"
+			continue
+		}
+		if !found {
+			parseError = errors.Join(parseError, fmt.Errorf("invalid environ entry %q", kv))
+			continue
+		}
+		env[key] = value
+	}
+
+	return env, parseError
+}
+
+// EnvChange is a change of one var in os.env
+type EnvChange struct {
+	Key string
+	// Value of the var before the change, if it was set
+	Previous string
+	WasSet   bool
+}
+
+// EnvJournal records changes of os.env, so they can be rolled back
+type EnvJournal struct {
+	changes []EnvChange
+}
+
+// Setenv sets var in os.env and records its previous value
+func (j *EnvJournal) Setenv(key, value string) error {
+	previous, wasSet := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		return err
+	}
+	j.changes = append(j.changes, EnvChange{Key: key, Previous: previous, WasSet: wasSet})
+	return nil
+}
+
+// Changes returns recorded changes in the order they were made
+func (j *EnvJournal) Changes() []EnvChange {
+	return j.changes
+}
+
+/*
+Rollback undoes recorded changes in reverse order, so os.env gets the values it had before the
+first change. The journal is cleared, so calling Rollback again does nothing.
+*/
+func (j *EnvJournal) Rollback() error {
+	var rollbackError error = nil
+
+	for i := len(j.changes) - 1; i >= 0; i-- {
+		change := j.changes[i]
+
+		var err error
+		if change.WasSet {
+			err = os.Setenv(change.Key, change.Previous)
+		} else {
+			err = os.Unsetenv(change.Key)
+		}
+		if err != nil {
+			rollbackError = errors.Join(
+				rollbackError,
+				fmt.Errorf("failed to restore env var %s\n\t%w", change.Key, err),
+			)
+		}
+	}
+	j.changes = nil
+
+	return rollbackError
+}
+
+/*
+This function sets vars from env which are not set in os.env or have another value there.
+Changes are recorded in the returned journal. If any var fails to be set, changes made so far are
+rolled back, so os.env is either fully updated or left as it was.
+*/
+func SetProcessEnv(env map[string]string) (*EnvJournal, error) {
+	journal := &EnvJournal{}
+
+	for key, value := range env {
+		if current, isSet := os.LookupEnv(key); isSet && current == value {
+			continue
+		}
+		if err := journal.Setenv(key, value); err != nil {
+			setError := fmt.Errorf("failed to set env var %s\n\t%w", key, err)
+			return nil, errors.Join(setError, journal.Rollback())
+		}
+	}
+
+	return journal, nil
+}
@@ -0,0 +1,139 @@
+package internal_test
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func saveAndClearEnv(keys []string) map[string]*string {
+	original := make(map[string]*string)
+	for _, key := range keys {
+		if val, ok := os.LookupEnv(key); ok {
+			v := val // копия строки
+			original[key] = &v
+			_ = os.Unsetenv(key)
+		} else {
+			original[key] = nil
+		}
+	}
+	return original
+}
+
+func restoreEnv(env map[string]*string) {
+	for key, val := range env {
+		if val == nil {
+			_ = os.Unsetenv(key)
+		} else {
+			_ = os.Setenv(key, *val)
+		}
+	}
+}
+
+func TestParseEnviron(t *testing.T) {
+	t.Run("Valid environ", func(t *testing.T) {
+		env, err := internal.ParseEnviron([]string{
+			"HOST=localhost",
+			"URL=postgres://host/db?sslmode=disable",
+			"EMPTY=",
+			"=C:=C:\\work",
+			"HOST=127.0.0.1",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		expected := map[string]string{
+			"HOST":  "127.0.0.1",
+			"URL":   "postgres://host/db?sslmode=disable",
+			"EMPTY": "",
+		}
+		if !reflect.DeepEqual(env, expected) {
+			t.Errorf("expected: %v\n Actual: %v", expected, env)
+		}
+	})
+
+	t.Run("Invalid environ", func(t *testing.T) {
+		_, err := internal.ParseEnviron([]string{"HOST=localhost", "BROKEN"})
+		if err == nil {
+			t.Fatal("expected to get an error")
+		}
+
+		const expectedErrorText = "invalid environ entry \"BROKEN\""
+		if !strings.Contains(err.Error(), expectedErrorText) {
+			t.Fatalf("expected \"%s\" to contain \"%s\"", err.Error(), expectedErrorText)
+		}
+	})
+}
+
+func TestSetProcessEnv(t *testing.T) {
+	keys := []string{"ENVIRON_KEPT", "ENVIRON_CHANGED", "ENVIRON_NEW"}
+	originalEnv := saveAndClearEnv(keys)
+	defer restoreEnv(originalEnv)
+
+	t.Setenv("ENVIRON_KEPT", "kept")
+	t.Setenv("ENVIRON_CHANGED", "old")
+
+	journal, err := internal.SetProcessEnv(map[string]string{
+		"ENVIRON_CHANGED": "new",
+		"ENVIRON_NEW":     "value",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+
+	checkProcessEnv(t, map[string]*string{
+		"ENVIRON_KEPT":    StringPtr("kept"),
+		"ENVIRON_CHANGED": StringPtr("new"),
+		"ENVIRON_NEW":     StringPtr("value"),
+	})
+
+	if changes := len(journal.Changes()); changes != 2 {
+		t.Errorf("expected 2 recorded changes, got %d", changes)
+	}
+
+	t.Run("Rollback", func(t *testing.T) {
+		if err := journal.Rollback(); err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		expected := map[string]*string{
+			"ENVIRON_KEPT":    StringPtr("kept"),
+			"ENVIRON_CHANGED": StringPtr("old"),
+			"ENVIRON_NEW":     nil,
+		}
+		checkProcessEnv(t, expected)
+
+		// The journal is cleared, so changes made after the rollback are kept
+		t.Setenv("ENVIRON_NEW", "later")
+		if err := journal.Rollback(); err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		expected["ENVIRON_NEW"] = StringPtr("later")
+		checkProcessEnv(t, expected)
+	})
+}
+
+// checkProcessEnv checks os.env, nil value means that var must not be set
+func checkProcessEnv(t *testing.T, expectedVars map[string]*string) {
+	for key, expectedValue := range expectedVars {
+		value, isSet := os.LookupEnv(key)
+		if expectedValue == nil {
+			if isSet {
+				t.Errorf("environment variable %s must not be set, but it is %s", key, value)
+			}
+			continue
+		}
+		if !isSet || value != *expectedValue {
+			t.Errorf(
+				"environment variable %s has value %s, expected %s",
+				key,
+				value,
+				*expectedValue,
+			)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// errDependencyFailed marks errors of entries which reference another invalid entry
+var errDependencyFailed = errors.New("referenced var is invalid")
+
+type resolveFunc func(name string) (string, error)
+
+/*
+This function expands references to other variables in values of dotenv entries:
+  - $VAR and ${VAR} are replaced with the value of VAR;
+  - ${VAR:-default} is replaced with default if VAR is unset or empty;
+  - ${VAR:?message} fails with message if VAR is unset or empty.
+
+Like in shells, a reference is resolved against the closest entry with such key above it, or
+against lookupEnv if there is none, so keys defined further down are not seen and references can
+not form cycles. Unless p is FileWins, vars set in lookupEnv are preferred, so references see the
+same value the var will finally have. A var referencing itself (PATH=$PATH:/bin) gets its previous
+definition in the file or its value from lookupEnv. Single-quoted values and "\$" in double-quoted
+values are not expanded.
+*/
+func ExpandDotenv(
+	entries []DotenvEntry,
+	lookupEnv func(string) (string, bool),
+	p Precedence,
+) ([]DotenvEntry, error) {
+	preferEnv := p != FileWins
+	// Values of entries above the current one, and keys whose closest entry failed
+	values := make(map[string]string)
+	failed := make(map[string]bool)
+
+	resolve := func(name string) (string, error) {
+		if value, isSet := lookupEnv(name); isSet && preferEnv {
+			return value, nil
+		}
+		if failed[name] {
+			return "", errDependencyFailed
+		}
+		if value, ok := values[name]; ok {
+			return value, nil
+		}
+		value, _ := lookupEnv(name)
+		return value, nil
+	}
+
+	var expandError error = nil
+	expanded := make([]DotenvEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		value, err := expandValue(entry.RawValue, entry.Quote, resolve)
+		if err != nil {
+			if !errors.Is(err, errDependencyFailed) {
+				err = fmt.Errorf("%s: var %s: %w", entryLocation(entry), entry.Key, err)
+				expandError = errors.Join(expandError, err)
+			}
+			failed[entry.Key] = true
+			delete(values, entry.Key)
+			continue
+		}
+
+		delete(failed, entry.Key)
+		values[entry.Key] = value
+		entry.Value = value
+		expanded = append(expanded, entry)
+	}
+
+	return expanded, expandError
+}
+
+func entryLocation(entry DotenvEntry) string {
+	if entry.File == "" {
+		return fmt.Sprintf("line %d", entry.Line)
+	}
+	return fmt.Sprintf("%s:%d", entry.File, entry.Line)
+}
+
+/*
+expandValue decodes raw value of an entry. Escapes are decoded only in double-quoted values,
+single-quoted values are returned as is. If resolve is nil, references are not expanded.
+*/
+func expandValue(raw string, quote QuoteStyle, resolve resolveFunc) (string, error) {
+	if quote == SingleQuoted {
+		return strings.ReplaceAll(raw, "\r\n", "\n"), nil
+	}
+
+	var value strings.Builder
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == '\r' && strings.HasPrefix(raw[i:], "\r\n"):
+			continue
+		case c == '\\' && quote == DoubleQuoted && i+1 < len(raw):
+			i++
+			switch raw[i] {
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case '"', '\\', '$':
+				value.WriteByte(raw[i])
+			default:
+				// Unknown escapes are kept as is
+				value.WriteByte('\\')
+				value.WriteByte(raw[i])
+			}
+		case c == '$' && resolve != nil:
+			expanded, n, err := expandReference(raw[i:], quote, resolve)
+			if err != nil {
+				return "", err
+			}
+			value.WriteString(expanded)
+			i += n - 1
+		default:
+			value.WriteByte(c)
+		}
+	}
+
+	return value.String(), nil
+}
+
+/*
+expandReference expands reference at the beginning of s (s[0] is "$") and returns its value and
+length. "$" which does not start a reference is kept as is.
+*/
+func expandReference(s string, quote QuoteStyle, resolve resolveFunc) (string, int, error) {
+	if len(s) > 1 && isNameStart(s[1]) {
+		n := 1 + nameLength(s[1:])
+		value, err := resolve(s[1:n])
+		return value, n, err
+	}
+
+	if !strings.HasPrefix(s, "${") {
+		return "$", 1, nil
+	}
+
+	end := closingBrace(s)
+	if end < 0 {
+		return "", 0, errors.New("unterminated \"${\"")
+	}
+	body := s[2:end]
+
+	name := body[:nameLength(body)]
+	if name == "" || !isNameStart(name[0]) {
+		return "", 0, fmt.Errorf("bad substitution \"%s\"", s[:end+1])
+	}
+
+	value, err := resolve(name)
+	if err != nil {
+		return "", 0, err
+	}
+
+	switch operator := body[len(name):]; {
+	case operator == "":
+	case strings.HasPrefix(operator, ":-"):
+		if value == "" {
+			value, err = expandValue(operator[2:], quote, resolve)
+		}
+	case strings.HasPrefix(operator, ":?"):
+		if value == "" {
+			message := operator[2:]
+			if message == "" {
+				message = "is not set"
+			}
+			err = fmt.Errorf("%s: %s", name, message)
+		}
+	default:
+		err = fmt.Errorf("bad substitution \"%s\"", s[:end+1])
+	}
+
+	return value, end + 1, err
+}
+
+// closingBrace returns index of "}" closing "${" at the beginning of s, or -1
+func closingBrace(s string) int {
+	depth := 0
+	for i := 2; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}' && depth == 0:
+			return i
+		case s[i] == '}':
+			depth--
+		}
+	}
+	return -1
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func nameLength(s string) int {
+	n := 0
+	for n < len(s) && (isNameStart(s[n]) || (s[n] >= '0' && s[n] <= '9')) {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,178 @@
+package internal_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func lookupInMap(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestExpandDotenv(t *testing.T) {
+	processEnv := map[string]string{
+		"HOME":  "/home/user",
+		"PATH":  "/usr/bin",
+		"EMPTY": "",
+	}
+
+	t.Run("Valid references", func(t *testing.T) {
+		const content = `
+HOST=localhost
+PORT=5432
+URL=postgres://${HOST}:$PORT/db
+CACHE=$HOME/.cache
+FORWARD=${LATER}
+LATER=later
+REDEFINED=first
+BEFORE=$REDEFINED
+REDEFINED=second
+PATH=$PATH:/opt/bin
+PATH=$PATH:/sbin
+WITH_DEFAULT=${MISSING:-${EMPTY:-fallback}}
+NOT_DEFAULT=${HOST:-fallback}
+SINGLE='$HOST'
+ESCAPED="\$HOST \${PORT} ${HOST}"
+PRICE=5$
+`
+		expectedValues := map[string]string{
+			"URL":          "postgres://localhost:5432/db",
+			"CACHE":        "/home/user/.cache",
+			"FORWARD":      "",
+			"BEFORE":       "first",
+			"PATH":         "/usr/bin:/opt/bin:/sbin",
+			"WITH_DEFAULT": "fallback",
+			"NOT_DEFAULT":  "localhost",
+			"SINGLE":       "$HOST",
+			"ESCAPED":      "$HOST ${PORT} localhost",
+			"PRICE":        "5$",
+		}
+
+		entries, err := internal.ParseDotenv("", content)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		entries, err = internal.ExpandDotenv(entries, lookupInMap(processEnv), internal.FileWins)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		values := make(map[string]string)
+		for _, entry := range entries {
+			values[entry.Key] = entry.Value
+		}
+
+		for key, expectedValue := range expectedValues {
+			if values[key] != expectedValue {
+				t.Errorf(
+					"var %s has value \"%s\", expected \"%s\"",
+					key,
+					values[key],
+					expectedValue,
+				)
+			}
+		}
+	})
+
+	t.Run("Process environment is preferred", func(t *testing.T) {
+		entries, err := internal.ParseDotenv("", "HOME=/root\nCACHE=$HOME/.cache")
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		expectedValues := map[internal.Precedence]string{
+			internal.ProcessEnvWins:  "/home/user/.cache",
+			internal.ErrorOnConflict: "/home/user/.cache",
+			internal.FileWins:        "/root/.cache",
+		}
+
+		for precedence, expectedValue := range expectedValues {
+			expanded, err := internal.ExpandDotenv(entries, lookupInMap(processEnv), precedence)
+			if err != nil {
+				t.Fatalf("unexpected error \"%v\"", err)
+			}
+
+			if expanded[1].Value != expectedValue {
+				t.Errorf(
+					"%v: CACHE has value \"%s\", expected \"%s\"",
+					precedence,
+					expanded[1].Value,
+					expectedValue,
+				)
+			}
+		}
+	})
+
+	t.Run("Invalid references", func(t *testing.T) {
+		testCases := []struct {
+			name              string
+			content           string
+			expectedErrorText string
+		}{
+			{
+				name:              "Required var is not set",
+				content:           "URL=${HOST:?host must be set}",
+				expectedErrorText: "line 1: var URL: HOST: host must be set",
+			},
+			{
+				name:              "Required var is empty",
+				content:           "URL=${EMPTY:?}",
+				expectedErrorText: "var URL: EMPTY: is not set",
+			},
+			{
+				name:              "Unterminated reference",
+				content:           "A=${B",
+				expectedErrorText: "var A: unterminated \"${\"",
+			},
+			{
+				name:              "Bad substitution",
+				content:           "A=${B:=x}",
+				expectedErrorText: "var A: bad substitution \"${B:=x}\"",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				entries, err := internal.ParseDotenv("", tc.content)
+				if err != nil {
+					t.Fatalf("unexpected error \"%v\"", err)
+				}
+
+				_, err = internal.ExpandDotenv(entries, lookupInMap(processEnv), internal.FileWins)
+				if err == nil {
+					t.Fatal("expected to get an error")
+				}
+
+				if !strings.Contains(err.Error(), tc.expectedErrorText) {
+					t.Fatalf("expected \"%s\" to contain \"%s\"", err.Error(), tc.expectedErrorText)
+				}
+			})
+		}
+	})
+
+	t.Run("Invalid reference is reported once", func(t *testing.T) {
+		entries, err := internal.ParseDotenv("", "A=${B:?}\nC=$A\nA=$A/x\nD=ok")
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		entries, err = internal.ExpandDotenv(entries, lookupInMap(processEnv), internal.FileWins)
+		if err == nil {
+			t.Fatal("expected to get an error")
+		}
+
+		if count := strings.Count(err.Error(), "B: is not set"); count != 1 {
+			t.Errorf("expected invalid reference to be reported once, got \"%v\"", err)
+		}
+
+		if len(entries) != 1 || entries[0].Key != "D" {
+			t.Errorf("expected only valid entries to be returned, got %v", entries)
+		}
+	})
+}
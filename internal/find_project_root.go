@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// Files and directories which mark the root of a project
+var projectRootMarkers = []string{".env.yaml", "go.mod", ".git"}
+
+/*
+This function walks up from start to the nearest directory which holds .env.yaml, go.mod or .git and
+returns it. If there is no such directory, start itself is returned.
+*/
+func FindProjectRoot(start string) (string, error) {
+	start, err := filepath.Abs(start)
+	if err != nil {
+		return "", errors.Join(errors.New("failed to get absolute path of start directory"), err)
+	}
+
+	for dir := start; ; {
+		for _, marker := range projectRootMarkers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				return dir, nil
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return start, nil
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,87 @@
+package internal_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestFindProjectRoot(t *testing.T) {
+	testCases := []struct {
+		name        string
+		marker      string
+		markerIsDir bool
+	}{
+		{name: ".env.yaml marks the root", marker: ".env.yaml"},
+		{name: "go.mod marks the root", marker: "go.mod"},
+		{name: ".git marks the root", marker: ".git", markerIsDir: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			start := filepath.Join(root, "cmd", "server")
+			if err := os.MkdirAll(start, 0o755); err != nil {
+				t.Fatalf("failed to create %s: %v", start, err)
+			}
+
+			markerPath := filepath.Join(root, tc.marker)
+			var err error
+			if tc.markerIsDir {
+				err = os.Mkdir(markerPath, 0o755)
+			} else {
+				err = os.WriteFile(markerPath, nil, 0o644)
+			}
+			if err != nil {
+				t.Fatalf("failed to create %s: %v", markerPath, err)
+			}
+
+			found, err := internal.FindProjectRoot(start)
+			if err != nil {
+				t.Fatalf("unexpected error \"%v\"", err)
+			}
+
+			if found != root {
+				t.Errorf("expected root %s, got %s", root, found)
+			}
+		})
+	}
+
+	t.Run("Nearest marker wins", func(t *testing.T) {
+		root := t.TempDir()
+		nested := filepath.Join(root, "nested")
+		if err := os.Mkdir(nested, 0o755); err != nil {
+			t.Fatalf("failed to create %s: %v", nested, err)
+		}
+		markers := []string{filepath.Join(root, "go.mod"), filepath.Join(nested, ".env.yaml")}
+		for _, path := range markers {
+			if err := os.WriteFile(path, nil, 0o644); err != nil {
+				t.Fatalf("failed to create %s: %v", path, err)
+			}
+		}
+
+		found, err := internal.FindProjectRoot(nested)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		if found != nested {
+			t.Errorf("expected root %s, got %s", nested, found)
+		}
+	})
+
+	t.Run("No markers", func(t *testing.T) {
+		start := t.TempDir()
+
+		found, err := internal.FindProjectRoot(start)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		if found != start {
+			t.Errorf("expected start directory %s to be returned, got %s", start, found)
+		}
+	})
+}
@@ -2,7 +2,9 @@ package internal
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 )
@@ -15,51 +17,159 @@ const (
 	FloatType  SupportedVarType = "float"
 	BoolType   SupportedVarType = "bool"
 	AnyType    SupportedVarType = "any"
+	// Absolute URL, see VarEntry.Schemes and VarEntry.RequireHost
+	URLType SupportedVarType = "url"
+	// host:port pair with a port from 1 to 65535
+	HostPortType SupportedVarType = "hostport"
+	// IPv4 or IPv6 address, see VarEntry.IPVersion
+	IPType SupportedVarType = "ip"
+	// IP network in CIDR notation, like 10.0.0.0/8, see VarEntry.IPVersion
+	CIDRType SupportedVarType = "cidr"
+	// Port number from 1 to 65535
+	PortType SupportedVarType = "port"
+	// Duration like 1h30m, see VarEntry.Min and VarEntry.Max
+	DurationType SupportedVarType = "duration"
+	// Point in time in RFC 3339 format or VarEntry.Layout
+	TimeType SupportedVarType = "time"
+	// IANA time zone name like Europe/Berlin
+	TimezoneType SupportedVarType = "timezone"
+	// Cron expression with 5 fields, or 6 fields starting with seconds
+	CronType SupportedVarType = "cron"
+	// Number of bytes with an optional SI or IEC unit like 10MB or 512MiB
+	SizeType SupportedVarType = "size"
+	// Number with a unit declared by VarEntry.Unit and VarEntry.Units, like 50% or 100rps
+	QuantityType SupportedVarType = "quantity"
+	// Items split by VarEntry.Separator, each of them checked against VarEntry.Element
+	ListType SupportedVarType = "list"
+	// key=value entries split by VarEntry.Separator, values checked against VarEntry.Element
+	MapType SupportedVarType = "map"
+	// JSON document, optionally checked against VarEntry.JSONSchema
+	JSONType SupportedVarType = "json"
 )
 
+// Types which vars may have
+var supportedVarTypes = []SupportedVarType{
+	StringType, IntType, FloatType, BoolType, AnyType,
+	URLType, HostPortType, IPType, CIDRType, PortType,
+	DurationType, TimeType, TimezoneType, CronType,
+	SizeType, QuantityType, ListType, MapType, JSONType,
+}
+
 type VarEntry struct {
 	Name         string           `yaml:"name"`
-	Required     bool             `yaml:"required"`
+	Required     bool             `yaml:"required,omitempty"`
 	Type         SupportedVarType `yaml:"type"`
-	DefaultValue any              `yaml:"default_value"`
-	Pattern      *string          `yaml:"pattern"`
+	DefaultValue any              `yaml:"default_value,omitempty"`
+	Pattern      *string          `yaml:"pattern,omitempty"`
+	// Values of secret vars are redacted in errors
+	Secret bool `yaml:"secret,omitempty"`
+	// Values which the var may take, any value if empty
+	AllowedValues []string `yaml:"allowed_values,omitempty"`
+	// Human readable description of the var
+	Description string `yaml:"description,omitempty"`
+	// Section of the documentation which the var belongs to
+	Group string `yaml:"group,omitempty"`
+	// Schemes which a url var may have, any scheme if empty
+	Schemes []string `yaml:"schemes,omitempty"`
+	// A url var must have a host
+	RequireHost bool `yaml:"require_host,omitempty"`
+	// IP version of an ip or cidr var, 4 or 6, any version if 0
+	IPVersion int `yaml:"ip_version,omitempty"`
+	// Lower bound of a duration, size or quantity var, written as its value
+	Min string `yaml:"min,omitempty"`
+	// Upper bound of a duration, size or quantity var, written as its value
+	Max string `yaml:"max,omitempty"`
+	// Layout of a time var in the format of time.Parse, RFC 3339 if empty
+	Layout string `yaml:"layout,omitempty"`
+	// Unit of a quantity var, which values are normalized to
+	Unit string `yaml:"unit,omitempty"`
+	// Other units of a quantity var and how many of Unit each of them is
+	Units map[string]float64 `yaml:"units,omitempty"`
+	// Separator of list items or map entries, "," if empty
+	Separator string `yaml:"separator,omitempty"`
+	// Separator of keys and values in map entries, "=" if empty
+	KeyValueSeparator string `yaml:"key_value_separator,omitempty"`
+	// Type, pattern, allowed values and options of list items or map values, string if not set
+	Element *VarEntry `yaml:"element,omitempty"`
+	// Least number of list items or map entries
+	MinItems int `yaml:"min_items,omitempty"`
+	// Greatest number of list items or map entries, no limit if 0
+	MaxItems int `yaml:"max_items,omitempty"`
+	// Items of a list var must not repeat
+	Unique bool `yaml:"unique,omitempty"`
+	// Schema which the value of a json var must match, any JSON if not set
+	JSONSchema *JSONSchema `yaml:"json_schema,omitempty"`
+}
+
+/*
+JSONSchema is the subset of JSON Schema which json vars may declare: type of a node, properties
+and required properties of objects, items of arrays and allowed values of any node.
+*/
+type JSONSchema struct {
+	// object, array, string, number, integer, boolean or null, any type if empty
+	Type string `yaml:"type,omitempty"`
+	// Schemas of properties of an object, other properties are not checked
+	Properties map[string]JSONSchema `yaml:"properties,omitempty"`
+	// Properties which an object must have
+	Required []string `yaml:"required,omitempty"`
+	// Schema of every item of an array
+	Items *JSONSchema `yaml:"items,omitempty"`
+	// Values which the node may take, compared as JSON
+	Enum []any `yaml:"enum,omitempty"`
 }
 
 type Config struct {
 	Vars []VarEntry `yaml:"vars"`
 }
 
-func checkRequiredFields(config *Config) error {
+// CheckRequiredFields makes sure that config can be used for validation
+func CheckRequiredFields(config *Config) error {
 	for _, v := range config.Vars {
 		if v.Name == "" {
-			return errors.New("config has var without name")
+			return &SchemaError{
+				ErrorDetails: ErrorDetails{Rule: "name"},
+				Message:      "config has var without name",
+			}
 		}
 	}
 
 	return nil
 }
 
+// ReadFileFunc reads the named file, os.ReadFile is the default one
+type ReadFileFunc func(name string) ([]byte, error)
+
+/*
+This function reads .env.yaml file specified by path and returns config.
+*/
+func GetConfig(path string) (Config, error) {
+	return ReadConfig(os.ReadFile, path)
+}
+
 /*
-This function reads .env.yaml file and returns config.
+This function reads config file specified by path with readFile and returns config.
+It allows to read config from fs.FS, for example from embed.FS.
 */
-func GetConfig() (Config, error) {
-	data, err := os.ReadFile(".env.yaml")
+func ReadConfig(readFile ReadFileFunc, path string) (Config, error) {
+	name := filepath.Base(path)
+
+	data, err := readFile(path)
 	if err != nil {
-		return Config{}, errors.Join(errors.New("failed to read .env.yaml"), err)
+		return Config{}, errors.Join(fmt.Errorf("failed to read %s", name), err)
 	}
 
 	var config Config
 	err = yaml.Unmarshal(data, &config)
 	if err != nil {
 		return Config{}, errors.Join(
-			errors.New("failed to unmarshal .env.yaml. Probably incorrect yaml structure"),
+			fmt.Errorf("failed to unmarshal %s. Probably incorrect yaml structure", name),
 			err,
 		)
 	}
 
-	err = checkRequiredFields(&config)
+	err = CheckSchema(&config)
 	if err != nil {
-		return Config{}, errors.Join(errors.New("failed to check required fields"), err)
+		return Config{}, errors.Join(errors.New("failed to check schema"), err)
 	}
 
 	return config, nil
@@ -29,7 +29,7 @@ func testGetConfigWithError(t *testing.T, configFileContent *string, expectedErr
 		t.Fatalf("failed to chdir to %s", tempdir)
 	}
 
-	_, err = internal.GetConfig()
+	_, err = internal.GetConfig(".env.yaml")
 	if err == nil {
 		t.Fatal("expected to get an error")
 	}
@@ -50,7 +50,6 @@ vars:
     default_value: 0
   - name: secondVar
     required: true
-    type: string
     pattern: .*secret.*
 `
 		expectedConfig := internal.Config{
@@ -82,7 +81,7 @@ vars:
 			t.Fatalf("failed to chdir to %s", tempdir)
 		}
 
-		c, err := internal.GetConfig()
+		c, err := internal.GetConfig(".env.yaml")
 		if err != nil {
 			t.Fatalf("failed to read env config: %v", err)
 		}
@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Types tried by InferSchema, from the most specific one
+var inferredTypes = []SupportedVarType{IntType, FloatType, BoolType}
+
+// Patterns proposed by InferSchema for string values of common shapes
+var inferredPatterns = []string{
+	`^[a-zA-Z][a-zA-Z0-9+.-]*://[^\s]+$`,
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`,
+	`^[^@\s]+@[^@\s]+\.[^@\s]+$`,
+}
+
+/*
+This function drafts a schema from entries of several .env files, one slice per file. Vars are
+listed in order of their first appearance.
+
+The type of a var is the first of int, float and bool which all of its non-empty values parse as
+(see ParseValue), string otherwise. Vars present in every file are required. String vars whose
+values all look like URLs, UUIDs or emails get a pattern. Comments of the first definition which
+has them become the description.
+*/
+func InferSchema(files [][]DotenvEntry) Config {
+	var order []string
+	vars := make(map[string]*inferredVar)
+
+	for _, entries := range files {
+		seen := make(map[string]bool)
+		for _, entry := range entries {
+			v, ok := vars[entry.Key]
+			if !ok {
+				v = &inferredVar{}
+				vars[entry.Key] = v
+				order = append(order, entry.Key)
+			}
+
+			if entry.Value != "" {
+				v.values = append(v.values, entry.Value)
+			}
+			if !seen[entry.Key] {
+				seen[entry.Key] = true
+				v.files++
+			}
+			if v.description == "" {
+				v.description = entryDescription(entry)
+			}
+		}
+	}
+
+	config := Config{Vars: make([]VarEntry, 0, len(order))}
+	for _, name := range order {
+		v := vars[name]
+		entry := VarEntry{
+			Name:        name,
+			Required:    v.files == len(files),
+			Type:        inferType(name, v.values),
+			Description: v.description,
+		}
+		if entry.Type == StringType {
+			entry.Pattern = inferPattern(v.values)
+		}
+		config.Vars = append(config.Vars, entry)
+	}
+
+	return config
+}
+
+type inferredVar struct {
+	// Non-empty values from all files
+	values      []string
+	files       int
+	description string
+}
+
+func inferType(name string, values []string) SupportedVarType {
+	if len(values) == 0 {
+		return StringType
+	}
+
+	for _, t := range inferredTypes {
+		if allValues(values, func(value string) bool {
+			_, err := ParseValue(VarEntry{Name: name, Type: t}, value)
+			return err == nil
+		}) {
+			return t
+		}
+	}
+	return StringType
+}
+
+func inferPattern(values []string) *string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	for _, pattern := range inferredPatterns {
+		re := regexp.MustCompile(pattern)
+		if allValues(values, re.MatchString) {
+			return &pattern
+		}
+	}
+	return nil
+}
+
+func allValues(values []string, ok func(string) bool) bool {
+	for _, value := range values {
+		if !ok(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// entryDescription joins comments of entry into one line
+func entryDescription(entry DotenvEntry) string {
+	var parts []string
+	for _, comment := range slices.Concat(entry.Comments, []string{entry.InlineComment}) {
+		if comment = strings.TrimSpace(comment); comment != "" {
+			parts = append(parts, comment)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,74 @@
+package internal_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestInferSchema(t *testing.T) {
+	const devFile = `
+# Port of the HTTP server
+PORT=8080
+RATIO=0.5
+DEBUG=true
+# Database
+DB_URL=postgres://localhost/db # local database
+REQUEST_ID=5f0c6a8e-3f7a-4d8e-9c1b-2a3b4c5d6e7f
+NAME=service
+EMPTY=
+`
+	const prodFile = `
+PORT=80
+RATIO=1
+DEBUG=false
+DB_URL=postgres://db.internal/db
+NAME=https://not.a.url/everywhere
+ONLY_PROD=1
+`
+
+	var files [][]internal.DotenvEntry
+	for _, content := range []string{devFile, prodFile} {
+		entries, err := internal.ParseDotenv("", content)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		files = append(files, entries)
+	}
+
+	expected := internal.Config{
+		Vars: []internal.VarEntry{
+			{
+				Name:        "PORT",
+				Required:    true,
+				Type:        internal.IntType,
+				Description: "Port of the HTTP server",
+			},
+			{Name: "RATIO", Required: true, Type: internal.FloatType},
+			{Name: "DEBUG", Required: true, Type: internal.BoolType},
+			{
+				Name:        "DB_URL",
+				Required:    true,
+				Type:        internal.StringType,
+				Pattern:     StringPtr(`^[a-zA-Z][a-zA-Z0-9+.-]*://[^\s]+$`),
+				Description: "Database local database",
+			},
+			{
+				Name: "REQUEST_ID",
+				Type: internal.StringType,
+				Pattern: StringPtr(
+					`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`,
+				),
+			},
+			{Name: "NAME", Required: true, Type: internal.StringType},
+			{Name: "EMPTY", Type: internal.StringType},
+			{Name: "ONLY_PROD", Type: internal.IntType},
+		},
+	}
+
+	config := internal.InferSchema(files)
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected config: %+v\n Actual config: %+v", expected, config)
+	}
+}
@@ -1,80 +1,191 @@
 package internal
 
 import (
-	"bufio"
-	"bytes"
 	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
+const DefaultEnvSelector = "APP_ENV"
+
+// DotenvFile is a .env file to load. Missing optional files are skipped silently.
+type DotenvFile struct {
+	Path     string
+	Optional bool
+}
+
+/*
+This function returns files which are loaded from dir for environment envName, in order of
+increasing priority: .env, .env.local, .env.<envName>, .env.<envName>.local. Only .env is required.
+If envName is empty, only .env and .env.local are returned.
+*/
+func DotenvCascade(dir, envName string) []DotenvFile {
+	files := []DotenvFile{
+		{Path: filepath.Join(dir, ".env")},
+		{Path: filepath.Join(dir, ".env.local"), Optional: true},
+	}
+	if envName != "" {
+		files = append(files,
+			DotenvFile{Path: filepath.Join(dir, ".env."+envName), Optional: true},
+			DotenvFile{Path: filepath.Join(dir, ".env."+envName+".local"), Optional: true},
+		)
+	}
+	return files
+}
+
 /*
 These functions reads .env file specified by path f.
-They iterate by lines and set os.env variables.
+They parse the file (see ParseDotenv for the supported syntax), expand references to other
+variables (see ExpandDotenv) and set vars in env according to precedence p (see ApplyDotenv).
 If the file is not found, it returns an error.
 */
-func LoadDotenvFromFile(f string) error {
+func LoadDotenvFromFile(f string, env map[string]string, p Precedence) ([]ShadowedVar, error) {
+	return LoadDotenv(DotenvOptions{Files: []DotenvFile{{Path: f}}, Env: env, Precedence: p})
+}
+
+type DotenvOptions struct {
+	// Directory with the cascade of files, usually the project root (see FindProjectRoot)
+	Dir string
+	// Var which selects per-environment files of the cascade, see DotenvCascade
+	Selector string
+	// Files to load instead of the cascade
+	Files []DotenvFile
+	// If set, .env of the cascade may be missing like the other files of the cascade
+	OptionalBase bool
+	// Snapshot of the process environment, vars from files are set in it. Must not be nil.
+	Env        map[string]string
+	Precedence Precedence
+	// Function which reads files, os.ReadFile if nil
+	ReadFile ReadFileFunc
+	// If not nil, sources of vars set from files are stored in it, see DotenvSources
+	Sources map[string]VarSource
+}
+
+/*
+Loads .env files as one: values from later files override values from earlier ones, references
+may point to vars defined above them in the same or in earlier files. Only after that the result
+is applied to o.Env according to o.Precedence.
+
+Unless o.Files is set, the cascade of files (see DotenvCascade) is loaded from o.Dir. Its
+per-environment files are picked by the value of o.Selector var, which is looked up in o.Env and
+then in .env and .env.local, with references expanded.
+*/
+func LoadDotenv(o DotenvOptions) ([]ShadowedVar, error) {
 	var loadError error = nil
 
-	fileBytes, err := os.ReadFile(f)
+	if o.ReadFile == nil {
+		o.ReadFile = os.ReadFile
+	}
+
+	files := o.Files
+	if files == nil {
+		files = DotenvCascade(o.Dir, "")
+		files[0].Optional = o.OptionalBase
+	}
+	entries, err := readDotenvFiles(o.ReadFile, files)
 	if err != nil {
-		return fmt.Errorf("failed to read .env\n\t %w", err)
+		loadError = errors.Join(fmt.Errorf("failed to load .env file\n\t%w", err), loadError)
 	}
 
-	// Read the file line by line
-	scanner := bufio.NewScanner(bytes.NewReader(fileBytes))
-	for scanner.Scan() {
-		line := scanner.Text()
-		// Skip empty lines and comments
-		if line == "" || line[0] == '#' {
-			continue
+	if o.Files == nil {
+		// The selector may reference other vars. Problems of expansion are reported when the
+		// entries are applied.
+		expanded, _ := ExpandDotenv(entries, LookupInMap(o.Env), o.Precedence)
+		envName, err := selectEnv(o.Selector, o.Env, expanded)
+		if err != nil {
+			loadError = errors.Join(err, loadError)
 		}
-		// Split the line into key and value
-		parts := strings.Split(line, "=")
-		if len(parts) != 2 {
-			loadError = errors.Join(fmt.Errorf("invalid line in .env file: %s", line), loadError)
-			continue
+		if envName != "" {
+			envFiles := DotenvCascade(o.Dir, envName)[len(files):]
+			envEntries, err := readDotenvFiles(o.ReadFile, envFiles)
+			if err != nil {
+				loadError = errors.Join(
+					fmt.Errorf("failed to load .env file\n\t%w", err),
+					loadError,
+				)
+			}
+			entries = append(entries, envEntries...)
 		}
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-
-		// If value is covered in quotes, remove them
-		if len(value) > 1 && value[0] == '"' && value[len(value)-1] == '"' {
-			value = value[1 : len(value)-1]
-		} else if len(value) > 1 && value[0] == '\'' && value[len(value)-1] == '\'' {
-			value = value[1 : len(value)-1]
+	}
+
+	shadowed, err := applyDotenvEntries(entries, o)
+	if err != nil {
+		loadError = errors.Join(err, loadError)
+	}
+
+	return shadowed, loadError
+}
+
+// selectEnv returns value of selector var from env or from entries
+func selectEnv(selector string, env map[string]string, entries []DotenvEntry) (string, error) {
+	if selector == "" {
+		return "", nil
+	}
+
+	envName, isSet := env[selector]
+	if !isSet {
+		for _, entry := range entries {
+			if entry.Key == selector {
+				envName = entry.Value
+			}
+		}
+	}
+
+	if strings.ContainsAny(envName, `/\`) || envName == "." || envName == ".." {
+		return "", fmt.Errorf("invalid environment name %q in %s", envName, selector)
+	}
+	return envName, nil
+}
+
+// readDotenvFiles reads and parses files, entries of all files are returned in one list
+func readDotenvFiles(readFile ReadFileFunc, files []DotenvFile) ([]DotenvEntry, error) {
+	var readError error = nil
+	var entries []DotenvEntry
+
+	for _, f := range files {
+		fileBytes, err := readFile(f.Path)
+		if err != nil {
+			if f.Optional && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			readError = errors.Join(readError, fmt.Errorf("failed to read .env\n\t %w", err))
+			continue
 		}
 
-		// Set the environment variable
-		if err := os.Setenv(key, value); err != nil {
-			loadError = errors.Join(
-				fmt.Errorf("failed to set env var %s\n\t%w", key, err),
-				loadError,
+		fileEntries, err := ParseDotenv(f.Path, string(fileBytes))
+		if err != nil {
+			readError = errors.Join(
+				readError,
+				fmt.Errorf("invalid .env file %s\n\t%w", f.Path, err),
 			)
 		}
+		entries = append(entries, fileEntries...)
 	}
-	return loadError
+
+	return entries, readError
 }
 
-/*
-Detects in which directory go.mod is located and loads .env file from there.
-*/
-func LoadDotenv() error {
-	var loadError error = nil
+// applyDotenvEntries expands references in entries and sets them in o.Env
+func applyDotenvEntries(entries []DotenvEntry, o DotenvOptions) ([]ShadowedVar, error) {
+	var applyError error = nil
 
-	// Get the current working directory
-	cwd, err := os.Getwd()
+	entries, err := ExpandDotenv(entries, LookupInMap(o.Env), o.Precedence)
 	if err != nil {
-		loadError = errors.Join(
-			errors.Join(errors.New("failed to get current working directory"), err),
-			loadError,
-		)
+		applyError = errors.Join(applyError, fmt.Errorf("failed to expand vars\n\t%w", err))
 	}
 
-	if err := LoadDotenvFromFile(cwd + "/.env"); err != nil {
-		loadError = errors.Join(fmt.Errorf("failed to load .env file\n\t%w", err), loadError)
+	shadowed, err := ApplyDotenv(entries, o.Env, o.Precedence)
+	if err != nil {
+		applyError = errors.Join(applyError, err)
+	}
+
+	if o.Sources != nil {
+		maps.Copy(o.Sources, DotenvSources(entries, o.Env))
 	}
 
-	return loadError
+	return shadowed, applyError
 }
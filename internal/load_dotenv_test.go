@@ -2,6 +2,7 @@ package internal_test
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/jogang0304/envcheck/internal"
@@ -15,6 +16,11 @@ ANOTHER_VAR="another_value"
 # Another comment
 SOME_VAR='some_value'
 last var =   last value
+export EXPORTED_VAR=exported_value
+BASE64_VAR=c2VjcmV0==
+URL_VAR=postgres://host/db?sslmode=disable&timeout=5 # inline comment
+MULTILINE_VAR="first line
+second\tline"
 `
 
 var expectedVars = map[string]string{
@@ -22,6 +28,11 @@ var expectedVars = map[string]string{
 	"ANOTHER_VAR": "another_value",
 	"SOME_VAR":    "some_value",
 	"last var":    "last value",
+
+	"EXPORTED_VAR":  "exported_value",
+	"BASE64_VAR":    "c2VjcmV0==",
+	"URL_VAR":       "postgres://host/db?sslmode=disable&timeout=5",
+	"MULTILINE_VAR": "first line\nsecond\tline",
 }
 
 func TestLoadDotenvFromFile(t *testing.T) {
@@ -37,35 +48,21 @@ func TestLoadDotenvFromFile(t *testing.T) {
 		}
 
 		// Load the .env file
-		err = internal.LoadDotenvFromFile(envFilePath)
+		env := make(map[string]string)
+		_, err = internal.LoadDotenvFromFile(envFilePath, env, internal.FileWins)
 		if err != nil {
 			t.Fatalf("failed to load .env file: %v", err)
 		}
 
-		// Check if the environment variables are set correctly
-		for key, expectedValue := range expectedVars {
-			value, exists := os.LookupEnv(key)
-			if !exists {
-				t.Errorf("environment variable %s is not set", key)
-				continue
-			}
-			if value != expectedValue {
-				t.Errorf(
-					"environment variable %s has value %s, expected %s",
-					key,
-					value,
-					expectedValue,
-				)
-			}
-		}
+		checkVars(t, env, expectedVars)
 	})
 
 	t.Run("Invalid .env file", func(t *testing.T) {
 		invalidContents := []string{
 			"INVALID_CONTENT",
-			"VAR1==value1",
-			"VAR2=value2=",
-			"VAR3=value3=value4",
+			"=value1",
+			"VAR2=\"value2",
+			"VAR3='value3' value4",
 		}
 
 		for _, content := range invalidContents {
@@ -80,7 +77,11 @@ func TestLoadDotenvFromFile(t *testing.T) {
 				}
 
 				// Load the .env file
-				err = internal.LoadDotenvFromFile(envFilePath)
+				_, err = internal.LoadDotenvFromFile(
+					envFilePath,
+					make(map[string]string),
+					internal.FileWins,
+				)
 				if err == nil {
 					t.Fatalf("should have failed because of invalid .env file content")
 				}
@@ -90,7 +91,7 @@ func TestLoadDotenvFromFile(t *testing.T) {
 }
 
 func TestLoadDotenv(t *testing.T) {
-	t.Run("Dotenv file exists in cwd", func(t *testing.T) {
+	t.Run("Dotenv file exists in dir", func(t *testing.T) {
 		tempdir := t.TempDir()
 
 		// Create a temporary .env file
@@ -100,48 +101,182 @@ func TestLoadDotenv(t *testing.T) {
 			t.Fatalf("failed to create .env file: %v", err)
 		}
 
-		err = os.Chdir(tempdir)
+		// Load the .env file
+		env := make(map[string]string)
+		_, err = internal.LoadDotenv(internal.DotenvOptions{
+			Dir:        tempdir,
+			Selector:   internal.DefaultEnvSelector,
+			Env:        env,
+			Precedence: internal.FileWins,
+		})
 		if err != nil {
-			t.Fatalf("failed to chdir to %s", tempdir)
+			t.Fatalf("failed to load .env file: %v", err)
 		}
 
+		checkVars(t, env, expectedVars)
+	})
+
+	t.Run("Dotenv file does not exist in dir", func(t *testing.T) {
+		tempdir := t.TempDir()
+
 		// Load the .env file
-		err = internal.LoadDotenv()
-		if err != nil {
-			t.Fatalf("failed to load .env file: %v", err)
+		_, err := internal.LoadDotenv(internal.DotenvOptions{
+			Dir:        tempdir,
+			Selector:   internal.DefaultEnvSelector,
+			Env:        make(map[string]string),
+			Precedence: internal.FileWins,
+		})
+		if err == nil {
+			t.Fatalf("should have failed because no .env file exists")
 		}
+	})
+}
 
-		// Check if the environment variables are set correctly
-		for key, expectedValue := range expectedVars {
-			value, exists := os.LookupEnv(key)
-			if !exists {
-				t.Errorf("environment variable %s is not set", key)
-				continue
-			}
-			if value != expectedValue {
-				t.Errorf(
-					"environment variable %s has value %s, expected %s",
-					key,
-					value,
-					expectedValue,
-				)
+func TestLoadDotenvCascade(t *testing.T) {
+	files := map[string]string{
+		".env":               "APP_ENV=staging\nSHARED=env\nLOCAL=env\nSTAGE=env\nSTAGE_LOCAL=env",
+		".env.local":         "LOCAL=local\nSTAGE=local\nSTAGE_LOCAL=local",
+		".env.staging":       "STAGE=staging\nSTAGE_LOCAL=staging\nURL=${SHARED}-${LOCAL}",
+		".env.staging.local": "STAGE_LOCAL=staging.local",
+		".env.production":    "STAGE=production",
+	}
+
+	createFiles := func(t *testing.T, names ...string) string {
+		tempdir := t.TempDir()
+		for _, name := range names {
+			err := os.WriteFile(tempdir+"/"+name, []byte(files[name]), 0o644)
+			if err != nil {
+				t.Fatalf("failed to create %s file: %v", name, err)
 			}
 		}
+
+		return tempdir
+	}
+
+	t.Run("Environment selected in .env", func(t *testing.T) {
+		dir := createFiles(
+			t,
+			".env",
+			".env.local",
+			".env.staging",
+			".env.staging.local",
+			".env.production",
+		)
+
+		env := make(map[string]string)
+		_, err := internal.LoadDotenv(internal.DotenvOptions{
+			Dir:        dir,
+			Selector:   internal.DefaultEnvSelector,
+			Env:        env,
+			Precedence: internal.ProcessEnvWins,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		checkVars(t, env, map[string]string{
+			"SHARED":      "env",
+			"LOCAL":       "local",
+			"STAGE":       "staging",
+			"STAGE_LOCAL": "staging.local",
+			"URL":         "env-local",
+		})
 	})
 
-	t.Run("Dotenv file does not exist in cwd", func(t *testing.T) {
-		// Change to a temporary directory
-		tempdir := t.TempDir()
+	t.Run("Environment selected in process, optional files are missing", func(t *testing.T) {
+		dir := createFiles(t, ".env", ".env.production")
+		env := map[string]string{"STAGE_NAME": "production"}
 
-		err := os.Chdir(tempdir)
+		_, err := internal.LoadDotenv(internal.DotenvOptions{
+			Dir:        dir,
+			Selector:   "STAGE_NAME",
+			Env:        env,
+			Precedence: internal.ProcessEnvWins,
+		})
 		if err != nil {
-			t.Fatalf("failed to chdir to %s", tempdir)
+			t.Fatalf("unexpected error \"%v\"", err)
 		}
 
-		// Load the .env file
-		err = internal.LoadDotenv()
+		checkVars(t, env, map[string]string{
+			"LOCAL":       "env",
+			"STAGE":       "production",
+			"STAGE_LOCAL": "env",
+		})
+	})
+
+	t.Run("Optional .env is missing", func(t *testing.T) {
+		dir := createFiles(t, ".env.production")
+		env := map[string]string{"APP_ENV": "production"}
+
+		_, err := internal.LoadDotenv(internal.DotenvOptions{
+			Dir:          dir,
+			Selector:     internal.DefaultEnvSelector,
+			OptionalBase: true,
+			Env:          env,
+			Precedence:   internal.ProcessEnvWins,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		checkVars(t, env, map[string]string{"STAGE": "production"})
+	})
+
+	t.Run("Environment selected by reference", func(t *testing.T) {
+		dir := createFiles(t, ".env.production")
+		dotenv := "NAME=production\nAPP_ENV=${NAME}\nSTAGE=env"
+		err := os.WriteFile(dir+"/.env", []byte(dotenv), 0o644)
+		if err != nil {
+			t.Fatalf("failed to create .env file: %v", err)
+		}
+		env := make(map[string]string)
+
+		_, err = internal.LoadDotenv(internal.DotenvOptions{
+			Dir:        dir,
+			Selector:   internal.DefaultEnvSelector,
+			Env:        env,
+			Precedence: internal.ProcessEnvWins,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		checkVars(t, env, map[string]string{"APP_ENV": "production", "STAGE": "production"})
+	})
+
+	t.Run("Invalid environment name", func(t *testing.T) {
+		dir := createFiles(t, ".env")
+		_, err := internal.LoadDotenv(internal.DotenvOptions{
+			Dir:        dir,
+			Selector:   internal.DefaultEnvSelector,
+			Env:        map[string]string{"APP_ENV": "../secrets"},
+			Precedence: internal.ProcessEnvWins,
+		})
 		if err == nil {
-			t.Fatalf("should have failed because no .env file exists")
+			t.Fatal("expected to get an error")
+		}
+
+		const expectedErrorText = "invalid environment name \"../secrets\" in APP_ENV"
+		if !strings.Contains(err.Error(), expectedErrorText) {
+			t.Fatalf("expected \"%s\" to contain \"%s\"", err.Error(), expectedErrorText)
 		}
 	})
 }
+
+func checkVars(t *testing.T, env map[string]string, expectedVars map[string]string) {
+	for key, expectedValue := range expectedVars {
+		value, exists := env[key]
+		if !exists {
+			t.Errorf("var %s is not set", key)
+			continue
+		}
+		if value != expectedValue {
+			t.Errorf(
+				"var %s has value %s, expected %s",
+				key,
+				value,
+				expectedValue,
+			)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*
+parseList splits value of list var v to items and parses each of them as v.Element. Errors of
+items are marked with their index, starting from 0.
+*/
+func parseList(v VarEntry, value string) ([]any, error) {
+	items := splitItems(v, value)
+	if reason := checkItemCount(v, len(items)); reason != "" {
+		return nil, typeErrorWithReason(v, value, reason)
+	}
+	if v.Unique {
+		for i, item := range items {
+			if j := slices.Index(items[:i], item); j != -1 {
+				reason := fmt.Sprintf("item %d repeats item %d", i, j)
+				return nil, typeErrorWithReason(v, value, reason)
+			}
+		}
+	}
+
+	element := elementEntry(v)
+	list := make([]any, 0, len(items))
+	var errs []error
+	for i, item := range items {
+		parsed, err := checkElement(element, item, strconv.Itoa(i))
+		var schemaErr *SchemaError
+		if errors.As(err, &schemaErr) {
+			return nil, err // the same for every item
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		list = append(list, parsed)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return list, nil
+}
+
+/*
+parseMap splits value of map var v to key=value entries and parses each value as v.Element.
+Errors of values are marked with their key.
+*/
+func parseMap(v VarEntry, value string) (map[string]any, error) {
+	entries := splitItems(v, value)
+	if reason := checkItemCount(v, len(entries)); reason != "" {
+		return nil, typeErrorWithReason(v, value, reason)
+	}
+
+	keys, items, reason := splitEntries(v, entries)
+	if reason != "" {
+		return nil, typeErrorWithReason(v, value, reason)
+	}
+
+	element := elementEntry(v)
+	m := make(map[string]any, len(entries))
+	var errs []error
+	for i, key := range keys {
+		parsed, err := checkElement(element, items[i], key)
+		var schemaErr *SchemaError
+		if errors.As(err, &schemaErr) {
+			return nil, err
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		m[key] = parsed
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return m, nil
+}
+
+/*
+splitEntries splits entries of map var v to keys and values, trimming spaces around them. If an
+entry is not a key-value pair or repeats a key, the reason is returned.
+*/
+func splitEntries(v VarEntry, entries []string) (keys, items []string, reason string) {
+	keys = make([]string, len(entries))
+	items = make([]string, len(entries))
+	for i, entry := range entries {
+		key, item, ok := strings.Cut(entry, keyValueSeparator(v))
+		key = strings.TrimSpace(key)
+
+		switch j := slices.Index(keys[:i], key); {
+		case !ok:
+			return nil, nil, fmt.Sprintf("entry %d must be key%svalue", i, keyValueSeparator(v))
+		case key == "":
+			return nil, nil, fmt.Sprintf("entry %d has an empty key", i)
+		case j != -1:
+			return nil, nil, fmt.Sprintf("entry %d repeats key of entry %d", i, j)
+		}
+		keys[i], items[i] = key, strings.TrimSpace(item)
+	}
+	return keys, items, ""
+}
+
+/*
+splitItems splits value of list or map var v by its separator and trims spaces around the items.
+A separator of spaces splits by any run of white space. Empty value has no items.
+*/
+func splitItems(v VarEntry, value string) []string {
+	separator := itemSeparator(v)
+	if strings.TrimSpace(separator) == "" {
+		return strings.Fields(value)
+	}
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	items := strings.Split(value, separator)
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
+
+func itemSeparator(v VarEntry) string {
+	if v.Separator == "" {
+		return ","
+	}
+	return v.Separator
+}
+
+func keyValueSeparator(v VarEntry) string {
+	if v.KeyValueSeparator == "" {
+		return "="
+	}
+	return v.KeyValueSeparator
+}
+
+// checkItemCount returns why count of items of var v is out of its limits, "" if it is within them
+func checkItemCount(v VarEntry, count int) string {
+	switch {
+	case count < v.MinItems:
+		return fmt.Sprintf("must have at least %d items, got %d", v.MinItems, count)
+	case v.MaxItems != 0 && count > v.MaxItems:
+		return fmt.Sprintf("must have at most %d items, got %d", v.MaxItems, count)
+	}
+	return ""
+}
+
+/*
+elementEntry returns declaration of items of list or map var v. It has the name of v, so errors
+of items are reported for v, and it is secret if v is.
+*/
+func elementEntry(v VarEntry) VarEntry {
+	element := VarEntry{Type: StringType}
+	if v.Element != nil {
+		element = *v.Element
+	}
+	if element.Type == "" {
+		element.Type = StringType
+	}
+	element.Name = v.Name
+	element.Secret = element.Secret || v.Secret
+	return element
+}
+
+// checkElement checks type, pattern and allowed values of an item and marks its errors as element
+func checkElement(v VarEntry, item, element string) (any, error) {
+	parsed, err := ParseValue(v, item)
+	err = errors.Join(err, checkPattern(v, item), checkAllowedValues(v, item))
+	if err != nil {
+		markElement(err, element)
+		return nil, err
+	}
+	return parsed, nil
+}
+
+/*
+checkCollectionOptions makes sure that separators, element and item limits are only set for the
+types which use them, and that items of lists and maps are not lists or maps themselves.
+*/
+func checkCollectionOptions(v VarEntry) error {
+	isCollection := v.Type == ListType || v.Type == MapType
+
+	var message string
+	switch {
+	case !isCollection && (v.Separator != "" || v.Element != nil || v.MinItems != 0 ||
+		v.MaxItems != 0):
+		message = fmt.Sprintf("var %s has list or map options, but its type is %s", v.Name, v.Type)
+	case v.KeyValueSeparator != "" && v.Type != MapType:
+		message = fmt.Sprintf("var %s has key_value_separator, but its type is %s", v.Name, v.Type)
+	case v.Unique && v.Type != ListType:
+		message = fmt.Sprintf("var %s has unique, but its type is %s", v.Name, v.Type)
+	case v.MinItems < 0 || v.MaxItems < 0:
+		message = fmt.Sprintf("var %s has negative min_items or max_items", v.Name)
+	case v.MaxItems != 0 && v.MinItems > v.MaxItems:
+		message = fmt.Sprintf("var %s has min_items %d greater than max_items %d",
+			v.Name, v.MinItems, v.MaxItems)
+	case v.Type == MapType && strings.Contains(keyValueSeparator(v), itemSeparator(v)):
+		message = fmt.Sprintf("var %s has key_value_separator which contains its separator", v.Name)
+	case v.Element != nil && (v.Element.Type == ListType || v.Element.Type == MapType):
+		message = fmt.Sprintf("var %s has element of type %s, lists and maps can not be nested",
+			v.Name, v.Element.Type)
+	}
+	if message == "" {
+		return nil
+	}
+
+	return &SchemaError{
+		ErrorDetails: ErrorDetails{Var: v.Name, Rule: "type"},
+		Message:      message,
+	}
+}
@@ -0,0 +1,203 @@
+package internal_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestParseCollectionValues(t *testing.T) {
+	pattern := "^[a-z]+$"
+
+	testCases := []struct {
+		name     string
+		v        internal.VarEntry
+		value    string
+		expected any
+	}{
+		{
+			name:     "List of strings",
+			v:        internal.VarEntry{Type: internal.ListType},
+			value:    "a, b ,c",
+			expected: []any{"a", "b", "c"},
+		},
+		{
+			name:     "Empty list",
+			v:        internal.VarEntry{Type: internal.ListType},
+			value:    " ",
+			expected: []any{},
+		},
+		{
+			name: "List of ports with separator",
+			v: internal.VarEntry{
+				Type:      internal.ListType,
+				Separator: ";",
+				Element:   &internal.VarEntry{Type: internal.PortType},
+			},
+			value:    "80;443",
+			expected: []any{80, 443},
+		},
+		{
+			name: "List split by spaces",
+			v: internal.VarEntry{
+				Type:      internal.ListType,
+				Separator: " ",
+				Element:   &internal.VarEntry{Type: internal.StringType, Pattern: &pattern},
+			},
+			value:    "alpha  beta\tgamma",
+			expected: []any{"alpha", "beta", "gamma"},
+		},
+		{
+			name: "Map of durations",
+			v: internal.VarEntry{
+				Type:    internal.MapType,
+				Element: &internal.VarEntry{Type: internal.DurationType, Max: "1m"},
+			},
+			value:    "read=5s, write = 10s",
+			expected: map[string]any{"read": 5 * time.Second, "write": 10 * time.Second},
+		},
+		{
+			name: "Map with separators",
+			v: internal.VarEntry{
+				Type:              internal.MapType,
+				Separator:         ";",
+				KeyValueSeparator: ":",
+				MinItems:          1,
+				MaxItems:          2,
+			},
+			value:    "team:core;url:https://example.com?a=b",
+			expected: map[string]any{"team": "core", "url": "https://example.com?a=b"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.v.Name = "VAR"
+			parsed, err := internal.ParseValue(tc.v, tc.value)
+			if err != nil {
+				t.Fatalf("unexpected error \"%v\"", err)
+			}
+			if !reflect.DeepEqual(parsed, tc.expected) {
+				t.Errorf("expected %#v, got %#v", tc.expected, parsed)
+			}
+		})
+	}
+}
+
+func TestParseCollectionValuesErrors(t *testing.T) {
+	ports := internal.VarEntry{
+		Type:     internal.ListType,
+		Element:  &internal.VarEntry{Type: internal.PortType},
+		MinItems: 1,
+		MaxItems: 3,
+		Unique:   true,
+	}
+	levels := internal.VarEntry{
+		Type: internal.MapType,
+		Element: &internal.VarEntry{
+			Type:          internal.StringType,
+			AllowedValues: []string{"debug", "info"},
+		},
+	}
+
+	testCases := []struct {
+		name               string
+		v                  internal.VarEntry
+		value              string
+		expectedErrorTexts []string
+	}{
+		{
+			name:  "Too few items",
+			v:     ports,
+			value: "",
+			expectedErrorTexts: []string{
+				"var VAR is not a valid list: must have at least 1 items, got 0",
+			},
+		},
+		{
+			name:  "Too many items",
+			v:     ports,
+			value: "1,2,3,4",
+			expectedErrorTexts: []string{
+				"var VAR is not a valid list: must have at most 3 items, got 4",
+			},
+		},
+		{
+			name:               "Repeated item",
+			v:                  ports,
+			value:              "80, 443, 80",
+			expectedErrorTexts: []string{"var VAR is not a valid list: item 2 repeats item 0"},
+		},
+		{
+			name:  "Invalid items",
+			v:     ports,
+			value: "80,http,0",
+			expectedErrorTexts: []string{
+				"var VAR[1] is not a valid port",
+				"var VAR[2] is not a valid port",
+			},
+		},
+		{
+			name:               "Entry without separator",
+			v:                  levels,
+			value:              "db=info,cache",
+			expectedErrorTexts: []string{"var VAR is not a valid map: entry 1 must be key=value"},
+		},
+		{
+			name:               "Empty key",
+			v:                  levels,
+			value:              "=info",
+			expectedErrorTexts: []string{"var VAR is not a valid map: entry 0 has an empty key"},
+		},
+		{
+			name:  "Repeated key",
+			v:     levels,
+			value: "db=info,http=debug,db=debug",
+			expectedErrorTexts: []string{
+				"var VAR is not a valid map: entry 2 repeats key of entry 0",
+			},
+		},
+		{
+			name:               "Value which is not allowed",
+			v:                  levels,
+			value:              "db=info,http=trace",
+			expectedErrorTexts: []string{"var VAR[http] is not one of allowed values debug, info"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.v.Name = "VAR"
+			_, err := internal.ParseValue(tc.v, tc.value)
+			if err == nil {
+				t.Fatal("expected to get an error")
+			}
+			for _, expected := range tc.expectedErrorTexts {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected \"%s\" to contain \"%s\"", err.Error(), expected)
+				}
+			}
+		})
+	}
+
+	t.Run("Element is reported in details", func(t *testing.T) {
+		v := internal.VarEntry{Name: "VAR", Type: internal.ListType, Secret: true}
+		v.Element = &internal.VarEntry{Type: internal.IntType}
+		_, err := internal.ParseValue(v, "1,two")
+
+		var typeErr *internal.TypeError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("expected TypeError, got \"%v\"", err)
+		}
+		details := typeErr.Details()
+		if details.Var != "VAR" || details.Element != "1" ||
+			details.Value != internal.RedactedValue {
+			t.Errorf("unexpected details %+v", details)
+		}
+	})
+
+}
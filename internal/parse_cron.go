@@ -0,0 +1,223 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+CronSchedule is the value of a cron var. Each field is a set of the values it matches, bit i is
+set if value i matches.
+*/
+type CronSchedule struct {
+	expression string
+	second     uint64
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	// Day of month or day of week starts with *, so a day must match both of them
+	anyDay bool
+}
+
+// String returns the expression which the schedule was parsed from
+func (s CronSchedule) String() string {
+	return s.expression
+}
+
+/*
+Matches tells whether the schedule fires at t, precise to a second. Like in cron, if both day of
+month and day of week are restricted, a day matches if either of them matches. A field which starts
+with *, even with a step, is not restricted.
+*/
+func (s CronSchedule) Matches(t time.Time) bool {
+	if !hasBit(s.second, t.Second()) || !hasBit(s.minute, t.Minute()) ||
+		!hasBit(s.hour, t.Hour()) || !hasBit(s.month, int(t.Month())) {
+		return false
+	}
+
+	dayOfMonth := hasBit(s.dayOfMonth, t.Day())
+	dayOfWeek := hasBit(s.dayOfWeek, int(t.Weekday()))
+	if s.anyDay {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+func hasBit(set uint64, i int) bool {
+	return set&(1<<uint(i)) != 0
+}
+
+// cronField describes values which a field of cron expression takes
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+	// The field takes ?, which means no specific value
+	anyAllowed bool
+}
+
+var (
+	secondField     = cronField{name: "second", min: 0, max: 59}
+	minuteField     = cronField{name: "minute", min: 0, max: 59}
+	hourField       = cronField{name: "hour", min: 0, max: 23}
+	dayOfMonthField = cronField{name: "day of month", min: 1, max: 31, anyAllowed: true}
+	monthField      = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is Sunday as well as 0
+	dayOfWeekField = cronField{name: "day of week", min: 0, max: 7, anyAllowed: true,
+		names: map[string]int{
+			"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+		},
+	}
+)
+
+// Expressions which the predefined schedules stand for
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// parseCron parses a cron expression: 5 fields for minute, hour, day of month, month and day of
+// week, or 6 fields which start with seconds. Fields take *, numbers, ranges like 1-5, steps like
+// */15 or 1-30/2 and comma-separated lists of them. Months and days of week may be given by their
+// first three letters, day of month and day of week may also be ?. Predefined schedules like
+// @daily are accepted as well.
+func parseCron(v VarEntry, value string) (CronSchedule, error) {
+	schedule, err := parseCronExpression(value)
+	if err != nil {
+		return CronSchedule{}, typeErrorWithReason(v, value, err.Error())
+	}
+	return schedule, nil
+}
+
+func parseCronExpression(expression string) (CronSchedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+		macro, ok := cronMacros[strings.ToLower(fields[0])]
+		if !ok {
+			return CronSchedule{}, fmt.Errorf("unknown schedule %s", fields[0])
+		}
+		fields = strings.Fields(macro)
+	}
+
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return CronSchedule{}, fmt.Errorf("expected 5 or 6 fields, got %d", len(fields))
+	}
+
+	schedule := CronSchedule{expression: expression}
+	targets := []struct {
+		set   *uint64
+		field cronField
+	}{
+		{&schedule.second, secondField},
+		{&schedule.minute, minuteField},
+		{&schedule.hour, hourField},
+		{&schedule.dayOfMonth, dayOfMonthField},
+		{&schedule.month, monthField},
+		{&schedule.dayOfWeek, dayOfWeekField},
+	}
+	for i, target := range targets {
+		set, err := target.field.parse(fields[i])
+		if err != nil {
+			return CronSchedule{}, fmt.Errorf("%s field: %w", target.field.name, err)
+		}
+		*target.set = set
+	}
+
+	// Sunday may be written as 7
+	if hasBit(schedule.dayOfWeek, 7) {
+		schedule.dayOfWeek |= 1
+	}
+	schedule.anyDay = isWildcard(fields[3]) || isWildcard(fields[5])
+	return schedule, nil
+}
+
+func isWildcard(field string) bool {
+	return strings.HasPrefix(field, "*") || field == "?"
+}
+
+// parse returns the set of values matched by a comma-separated list of ranges
+func (f cronField) parse(field string) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		first, last, step, err := f.parseRange(part)
+		if err != nil {
+			return 0, err
+		}
+		for i := first; i <= last; i += step {
+			set |= 1 << uint(i)
+		}
+	}
+	return set, nil
+}
+
+// parseRange parses *, a number or a range, each with an optional step
+func (f cronField) parseRange(part string) (first, last, step int, err error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+	step = 1
+	if hasStep {
+		step, err = strconv.Atoi(stepPart)
+		if err != nil || step < 1 || step > f.max-f.min+1 {
+			return 0, 0, 0, fmt.Errorf("step %q must be from 1 to %d", stepPart, f.max-f.min+1)
+		}
+	}
+
+	switch {
+	case rangePart == "*" || (rangePart == "?" && f.anyAllowed):
+		return f.min, f.max, step, nil
+	case rangePart == "?":
+		return 0, 0, 0, fmt.Errorf("? is only allowed for days")
+	case strings.Contains(rangePart, "-"):
+		from, to, _ := strings.Cut(rangePart, "-")
+		if first, err = f.parseValue(from); err != nil {
+			return 0, 0, 0, err
+		}
+		if last, err = f.parseValue(to); err != nil {
+			return 0, 0, 0, err
+		}
+		if first > last {
+			return 0, 0, 0, fmt.Errorf("range %s starts after it ends", rangePart)
+		}
+		return first, last, step, nil
+	default:
+		if first, err = f.parseValue(rangePart); err != nil {
+			return 0, 0, 0, err
+		}
+		// A value with a step, like 5/15, runs up to the maximum
+		if hasStep {
+			return first, f.max, step, nil
+		}
+		return first, first, step, nil
+	}
+}
+
+// parseValue parses a number or a name, which must be in the range of the field
+func (f cronField) parseValue(value string) (int, error) {
+	if i, ok := f.names[strings.ToLower(value)]; ok {
+		return i, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	if i < f.min || i > f.max {
+		return 0, fmt.Errorf("%d is out of range %d-%d", i, f.min, f.max)
+	}
+	return i, nil
+}
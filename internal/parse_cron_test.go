@@ -0,0 +1,126 @@
+package internal_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func parseCron(t *testing.T, expression string) internal.CronSchedule {
+	t.Helper()
+
+	v := internal.VarEntry{Name: "SCHEDULE", Type: internal.CronType}
+	parsed, err := internal.ParseValue(v, expression)
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+	return parsed.(internal.CronSchedule)
+}
+
+func TestParseCron(t *testing.T) {
+	// 2024-03-04 is Monday
+	at := func(day, hour, minute, second int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, second, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		expression string
+		matches    []time.Time
+		misses     []time.Time
+	}{
+		{
+			expression: "*/15 9-17 * * mon-fri",
+			matches:    []time.Time{at(4, 9, 0, 0), at(8, 17, 45, 0)},
+			misses: []time.Time{
+				at(4, 9, 5, 0),
+				at(4, 18, 0, 0),
+				at(9, 9, 0, 0),
+				at(4, 9, 0, 1),
+			},
+		},
+		{
+			expression: "30 0 12 1,15 * ?",
+			matches:    []time.Time{at(1, 12, 0, 30), at(15, 12, 0, 30)},
+			misses:     []time.Time{at(1, 12, 0, 0), at(2, 12, 0, 30)},
+		},
+		{
+			// Day of month and day of week both restricted, either of them matches
+			expression: "0 0 1 * 7",
+			matches:    []time.Time{at(1, 0, 0, 0), at(3, 0, 0, 0)},
+			misses:     []time.Time{at(2, 0, 0, 0)},
+		},
+		{
+			// Day of month with a step is not restricted, so both fields must match
+			expression: "0 0 */2 * 1",
+			matches:    []time.Time{at(11, 0, 0, 0), at(25, 0, 0, 0)},
+			misses:     []time.Time{at(3, 0, 0, 0), at(4, 0, 0, 0), at(5, 0, 0, 0)},
+		},
+		{
+			expression: "5/20 * * MAR *",
+			matches:    []time.Time{at(4, 1, 5, 0), at(4, 1, 45, 0)},
+			misses:     []time.Time{at(4, 1, 0, 0), time.Date(2024, 4, 1, 1, 5, 0, 0, time.UTC)},
+		},
+		{
+			expression: "@daily",
+			matches:    []time.Time{at(4, 0, 0, 0)},
+			misses:     []time.Time{at(4, 1, 0, 0)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			schedule := parseCron(t, tc.expression)
+			if schedule.String() != tc.expression {
+				t.Errorf("expected expression \"%s\", got \"%s\"", tc.expression, schedule)
+			}
+			for _, tm := range tc.matches {
+				if !schedule.Matches(tm) {
+					t.Errorf("expected schedule to match %v", tm)
+				}
+			}
+			for _, tm := range tc.misses {
+				if schedule.Matches(tm) {
+					t.Errorf("expected schedule not to match %v", tm)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	testCases := []struct {
+		expression        string
+		expectedErrorText string
+	}{
+		{"* * * *", "expected 5 or 6 fields, got 4"},
+		{"* * * * * * *", "expected 5 or 6 fields, got 7"},
+		{"60 * * * *", "minute field: 60 is out of range 0-59"},
+		{"0 24 * * *", "hour field: 24 is out of range 0-23"},
+		{"0 0 0 * *", "day of month field: 0 is out of range 1-31"},
+		{"0 0 * 13 *", "month field: 13 is out of range 1-12"},
+		{"0 0 * * 8", "day of week field: 8 is out of range 0-7"},
+		{"61 0 0 * * *", "second field: 61 is out of range 0-59"},
+		{"0 0 * foo *", "month field: \"foo\" is not a number"},
+		{"*/0 * * * *", "minute field: step \"0\" must be from 1 to 60"},
+		{"0 17-9 * * *", "hour field: range 17-9 starts after it ends"},
+		{"? * * * *", "minute field: ? is only allowed for days"},
+		{"@often", "unknown schedule @often"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			v := internal.VarEntry{Name: "SCHEDULE", Type: internal.CronType}
+			_, err := internal.ParseValue(v, tc.expression)
+			if err == nil {
+				t.Fatal("expected to get an error")
+			}
+
+			expected := "var SCHEDULE is not a valid cron: " + tc.expectedErrorText
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("expected \"%s\" to contain \"%s\"", err.Error(), expected)
+			}
+		})
+	}
+}
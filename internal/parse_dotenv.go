@@ -0,0 +1,316 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type QuoteStyle int
+
+const (
+	Unquoted QuoteStyle = iota
+	SingleQuoted
+	DoubleQuoted
+)
+
+func (q QuoteStyle) String() string {
+	switch q {
+	case SingleQuoted:
+		return "single-quoted"
+	case DoubleQuoted:
+		return "double-quoted"
+	default:
+		return "unquoted"
+	}
+}
+
+type DotenvEntry struct {
+	Key string
+	// Value as it is written in the file, without surrounding quotes
+	RawValue string
+	// Value with escapes decoded
+	Value string
+	Quote QuoteStyle
+	File  string
+	Line  int
+	// Comment lines directly above the entry, without "#"
+	Comments []string
+	// Comment following the value on the same line, without "#"
+	InlineComment string
+}
+
+/*
+ParseError points to the place in a dotenv file where parsing failed.
+Line and Column are 1-based, Column is counted in characters.
+*/
+type ParseError struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+type dotenvParser struct {
+	file      string
+	src       string
+	pos       int
+	line      int
+	lineStart int
+	// comment lines seen since the last entry or empty line
+	comments []string
+}
+
+/*
+This function parses contents of a dotenv file. It supports:
+  - values containing "=" (only the first "=" separates key from value);
+  - "export KEY=value" lines;
+  - comments on their own lines and after unquoted values ("KEY=value # comment");
+  - single-quoted values, which are taken literally;
+  - double-quoted values with escapes \n, \r, \t, \", \\ and \$;
+  - quoted values spanning several lines.
+
+Variable references ($VAR, ${VAR}) are not expanded here, see ExpandDotenv.
+
+Comments are attached to the entry they directly precede or follow on the same line. A comment
+block separated from the entry by an empty line is not attached.
+
+file is stored in entries and used in error messages. All errors are collected, the parser skips
+to the next line after each of them.
+*/
+func ParseDotenv(file string, src string) ([]DotenvEntry, error) {
+	p := &dotenvParser{file: file, src: src, line: 1}
+
+	var entries []DotenvEntry
+	var parseError error = nil
+
+	for !p.eof() {
+		entry, ok, err := p.parseLine()
+		if err != nil {
+			parseError = errors.Join(parseError, err)
+			p.comments = nil
+			p.skipLine()
+			continue
+		}
+		if ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, parseError
+}
+
+func (p *dotenvParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *dotenvParser) peek() byte {
+	return p.src[p.pos]
+}
+
+func (p *dotenvParser) atLineEnd() bool {
+	return p.eof() || p.peek() == '\n' || strings.HasPrefix(p.src[p.pos:], "\r\n")
+}
+
+// advance moves one byte forward keeping track of line numbers
+func (p *dotenvParser) advance() {
+	if p.peek() == '\n' {
+		p.line++
+		p.lineStart = p.pos + 1
+	}
+	p.pos++
+}
+
+func (p *dotenvParser) skipBlanks() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+// skipLine moves to the beginning of the next line
+func (p *dotenvParser) skipLine() {
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+	if !p.eof() {
+		p.advance()
+	}
+}
+
+func (p *dotenvParser) column(pos int) int {
+	return utf8.RuneCountInString(p.src[p.lineStart:pos]) + 1
+}
+
+func (p *dotenvParser) errorAt(line, column int, format string, args ...any) error {
+	return &ParseError{
+		File:    p.file,
+		Line:    line,
+		Column:  column,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *dotenvParser) errorHere(format string, args ...any) error {
+	return p.errorAt(p.line, p.column(p.pos), format, args...)
+}
+
+/*
+parseLine parses one "KEY=value" pair starting at the current position. ok is false for empty and
+comment lines.
+*/
+func (p *dotenvParser) parseLine() (entry DotenvEntry, ok bool, err error) {
+	p.skipBlanks()
+	if p.atLineEnd() {
+		p.comments = nil
+		p.skipLine()
+		return DotenvEntry{}, false, nil
+	}
+	if p.peek() == '#' {
+		p.comments = append(p.comments, p.readComment())
+		p.skipLine()
+		return DotenvEntry{}, false, nil
+	}
+
+	entry.File = p.file
+	entry.Line = p.line
+	entry.Comments = p.comments
+	p.comments = nil
+	keyStart := p.pos
+	for !p.atLineEnd() && p.peek() != '=' {
+		p.pos++
+	}
+	if p.atLineEnd() {
+		return DotenvEntry{}, false, p.errorHere("expected \"=\" after key")
+	}
+
+	key := strings.TrimSpace(p.src[keyStart:p.pos])
+	if rest, found := strings.CutPrefix(key, "export"); found && rest != "" &&
+		(rest[0] == ' ' || rest[0] == '\t') {
+		key = strings.TrimSpace(rest)
+	}
+	if key == "" {
+		return DotenvEntry{}, false, p.errorHere("missing key before \"=\"")
+	}
+	if i := strings.IndexAny(key, "\"'#$\\"); i >= 0 {
+		return DotenvEntry{}, false, p.errorAt(
+			p.line,
+			p.column(keyStart+strings.Index(p.src[keyStart:p.pos], key)+i),
+			"invalid character %q in key %q",
+			key[i],
+			key,
+		)
+	}
+	entry.Key = key
+
+	p.pos++ // skip "="
+	p.skipBlanks()
+
+	if p.eof() {
+		return entry, true, nil
+	}
+
+	switch p.peek() {
+	case '"':
+		entry.Quote = DoubleQuoted
+		entry.RawValue, err = p.parseQuoted('"', "double-quoted")
+	case '\'':
+		entry.Quote = SingleQuoted
+		entry.RawValue, err = p.parseQuoted('\'', "single-quoted")
+	default:
+		entry.Quote = Unquoted
+		entry.RawValue = p.parseUnquoted()
+	}
+	if err != nil {
+		return DotenvEntry{}, false, err
+	}
+
+	// Decoding without expansion can not fail
+	entry.Value, _ = expandValue(entry.RawValue, entry.Quote, nil)
+
+	entry.InlineComment, err = p.finishLine()
+	if err != nil {
+		return DotenvEntry{}, false, err
+	}
+
+	return entry, true, nil
+}
+
+/*
+parseUnquoted reads value until the end of line. "#" preceded by a blank starts a comment.
+Surrounding blanks are trimmed.
+*/
+func (p *dotenvParser) parseUnquoted() string {
+	start := p.pos
+	for !p.atLineEnd() {
+		c := p.peek()
+		if c == '#' && (p.src[p.pos-1] == ' ' || p.src[p.pos-1] == '\t') {
+			break
+		}
+		p.pos++
+	}
+	return strings.TrimRight(p.src[start:p.pos], " \t")
+}
+
+/*
+parseQuoted reads value up to the closing quote and returns it as is. Inside double quotes a
+backslash escapes the next character, so \" does not close the value.
+*/
+func (p *dotenvParser) parseQuoted(quote byte, kind string) (string, error) {
+	openLine, openColumn := p.line, p.column(p.pos)
+	p.advance() // skip opening quote
+
+	start := p.pos
+	for !p.eof() && p.peek() != quote {
+		if quote == '"' && p.peek() == '\\' {
+			p.advance()
+			if p.eof() {
+				break
+			}
+		}
+		p.advance()
+	}
+	if p.eof() {
+		return "", p.errorAt(openLine, openColumn, "unterminated %s value", kind)
+	}
+
+	value := p.src[start:p.pos]
+	p.advance() // skip closing quote
+	return value, nil
+}
+
+/*
+finishLine makes sure that only blanks and a comment follow the value. It returns text of the
+comment.
+*/
+func (p *dotenvParser) finishLine() (string, error) {
+	p.skipBlanks()
+	if p.atLineEnd() {
+		p.skipLine()
+		return "", nil
+	}
+	if p.peek() != '#' {
+		return "", p.errorHere("unexpected character %q after value", p.peek())
+	}
+
+	comment := p.readComment()
+	p.skipLine()
+	return comment, nil
+}
+
+// readComment returns text from "#" at the current position up to the end of line
+func (p *dotenvParser) readComment() string {
+	start := p.pos + 1
+	end := start
+	for end < len(p.src) && p.src[end] != '\n' {
+		end++
+	}
+	return strings.TrimSpace(p.src[start:end])
+}
@@ -0,0 +1,221 @@
+package internal_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func dotenvEntry(
+	key, rawValue, value string,
+	quote internal.QuoteStyle,
+	line int,
+) internal.DotenvEntry {
+	return internal.DotenvEntry{
+		Key:      key,
+		RawValue: rawValue,
+		Value:    value,
+		Quote:    quote,
+		Line:     line,
+	}
+}
+
+func withComments(
+	entry internal.DotenvEntry,
+	comments []string,
+	inlineComment string,
+) internal.DotenvEntry {
+	entry.Comments = comments
+	entry.InlineComment = inlineComment
+	return entry
+}
+
+func TestParseDotenv(t *testing.T) {
+	t.Run("Valid content", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			content  string
+			expected []internal.DotenvEntry
+		}{
+			{
+				name:    "Value with equal signs",
+				content: "KEY=a=b==",
+				expected: []internal.DotenvEntry{
+					dotenvEntry("KEY", "a=b==", "a=b==", internal.Unquoted, 1),
+				},
+			},
+			{
+				name:    "Export prefix",
+				content: "export KEY=value\nexport\tOTHER = other",
+				expected: []internal.DotenvEntry{
+					dotenvEntry("KEY", "value", "value", internal.Unquoted, 1),
+					dotenvEntry("OTHER", "other", "other", internal.Unquoted, 2),
+				},
+			},
+			{
+				name: "Inline comments",
+				content: "A=value # comment\nB=#not-a-comment\n" +
+					"C=\"quoted # value\" # comment\nD= # empty",
+				expected: []internal.DotenvEntry{
+					withComments(
+						dotenvEntry("A", "value", "value", internal.Unquoted, 1),
+						nil,
+						"comment",
+					),
+					dotenvEntry("B", "#not-a-comment", "#not-a-comment", internal.Unquoted, 2),
+					withComments(
+						dotenvEntry(
+							"C",
+							"quoted # value",
+							"quoted # value",
+							internal.DoubleQuoted,
+							3,
+						),
+						nil,
+						"comment",
+					),
+					withComments(dotenvEntry("D", "", "", internal.Unquoted, 4), nil, "empty"),
+				},
+			},
+			{
+				name: "Attached comments",
+				content: "# Detached comment\n\n# Database host\n#   used by the API\nHOST=db\n" +
+					"PORT=5432 #port",
+				expected: []internal.DotenvEntry{
+					withComments(
+						dotenvEntry("HOST", "db", "db", internal.Unquoted, 5),
+						[]string{"Database host", "used by the API"},
+						"",
+					),
+					withComments(
+						dotenvEntry("PORT", "5432", "5432", internal.Unquoted, 6),
+						nil,
+						"port",
+					),
+				},
+			},
+			{
+				name:    "Double-quoted escapes",
+				content: `KEY="a\nb\tc\"d\\e\$f\qg"`,
+				expected: []internal.DotenvEntry{
+					{
+						Key:      "KEY",
+						RawValue: `a\nb\tc\"d\\e\$f\qg`,
+						Value:    "a\nb\tc\"d\\e$f\\qg",
+						Quote:    internal.DoubleQuoted,
+						Line:     1,
+					},
+				},
+			},
+			{
+				name:    "Single-quoted values are literal",
+				content: `KEY='a\nb "c"'`,
+				expected: []internal.DotenvEntry{
+					dotenvEntry("KEY", `a\nb "c"`, `a\nb "c"`, internal.SingleQuoted, 1),
+				},
+			},
+			{
+				name: "Multiline values",
+				content: "KEY=\"-----BEGIN KEY-----\r\nabc\r\n-----END KEY-----\"\r\n" +
+					"OTHER='x\ny'\nLAST=last",
+				expected: []internal.DotenvEntry{
+					{
+						Key:      "KEY",
+						RawValue: "-----BEGIN KEY-----\r\nabc\r\n-----END KEY-----",
+						Value:    "-----BEGIN KEY-----\nabc\n-----END KEY-----",
+						Quote:    internal.DoubleQuoted,
+						Line:     1,
+					},
+					dotenvEntry("OTHER", "x\ny", "x\ny", internal.SingleQuoted, 4),
+					dotenvEntry("LAST", "last", "last", internal.Unquoted, 6),
+				},
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				entries, err := internal.ParseDotenv("", tc.content)
+				if err != nil {
+					t.Fatalf("unexpected error \"%v\"", err)
+				}
+
+				if !reflect.DeepEqual(entries, tc.expected) {
+					t.Errorf("expected entries: %v\n Actual entries: %v", tc.expected, entries)
+				}
+			})
+		}
+	})
+
+	t.Run("Invalid content", func(t *testing.T) {
+		testCases := []struct {
+			content           string
+			expectedLine      int
+			expectedColumn    int
+			expectedErrorText string
+		}{
+			{"A=1\nINVALID_CONTENT", 2, 16, "expected \"=\" after key"},
+			{"  =value", 1, 3, "missing key before \"=\""},
+			{"A=1\n\nKEY=\"value\nmore", 3, 5, "unterminated double-quoted value"},
+			{"KEY='value", 1, 5, "unterminated single-quoted value"},
+			{"KEY=\"value\" rest", 1, 13, "unexpected character 'r' after value"},
+			{"MY#KEY=value", 1, 3, "invalid character '#' in key \"MY#KEY\""},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.content, func(t *testing.T) {
+				_, err := internal.ParseDotenv(".env", tc.content)
+				if err == nil {
+					t.Fatal("expected to get an error")
+				}
+
+				var parseError *internal.ParseError
+				if !errors.As(err, &parseError) {
+					t.Fatalf("expected ParseError, got \"%v\"", err)
+				}
+
+				if parseError.Line != tc.expectedLine || parseError.Column != tc.expectedColumn {
+					t.Errorf(
+						"expected error at %d:%d, got %d:%d",
+						tc.expectedLine,
+						tc.expectedColumn,
+						parseError.Line,
+						parseError.Column,
+					)
+				}
+
+				if !strings.Contains(err.Error(), tc.expectedErrorText) {
+					t.Errorf("expected \"%s\" to contain \"%s\"", err.Error(), tc.expectedErrorText)
+				}
+			})
+		}
+	})
+
+	t.Run("Parsing continues after an error", func(t *testing.T) {
+		entries, err := internal.ParseDotenv(".env", "BROKEN\nKEY=value\nOTHER='x' y")
+		if err == nil {
+			t.Fatal("expected to get an error")
+		}
+
+		expected := []internal.DotenvEntry{
+			{
+				Key:      "KEY",
+				RawValue: "value",
+				Value:    "value",
+				Quote:    internal.Unquoted,
+				File:     ".env",
+				Line:     2,
+			},
+		}
+		if !reflect.DeepEqual(entries, expected) {
+			t.Errorf("expected entries: %v\n Actual entries: %v", expected, entries)
+		}
+
+		errorText := err.Error()
+		if !strings.Contains(errorText, ".env:1:7") || !strings.Contains(errorText, ".env:3:11") {
+			t.Errorf("expected both errors to be reported, got \"%v\"", err)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Types of JSON nodes which JSONSchema.Type may name
+var jsonTypes = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+/*
+parseJSON decodes value of json var v like encoding/json does to any, and checks it against
+v.JSONSchema. Every node which does not match the schema is reported with its JSON pointer.
+*/
+func parseJSON(v VarEntry, value string) (any, error) {
+	var parsed any
+	if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+		reason := "must be valid JSON"
+		if !v.Secret {
+			reason += ", " + err.Error() // the error may quote a part of the value
+		}
+		return nil, typeErrorWithReason(v, value, reason)
+	}
+
+	if v.JSONSchema != nil {
+		if err := checkJSONNode(v, *v.JSONSchema, parsed, ""); err != nil {
+			return nil, err
+		}
+	}
+	return parsed, nil
+}
+
+// checkJSONNode checks node at pointer against schema s and returns all mismatches
+func checkJSONNode(v VarEntry, s JSONSchema, node any, pointer string) error {
+	if s.Type != "" && !jsonTypeMatches(s.Type, node) {
+		reason := fmt.Sprintf("must be of type %s, got %s", s.Type, jsonTypeOf(node))
+		return jsonSchemaError(v, node, pointer, reason)
+	}
+	if len(s.Enum) > 0 && !slices.Contains(jsonTexts(s.Enum), jsonText(node)) {
+		reason := "must be one of " + strings.Join(jsonTexts(s.Enum), ", ")
+		return jsonSchemaError(v, node, pointer, reason)
+	}
+
+	var errs []error
+	switch n := node.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := n[name]; !ok {
+				reason := fmt.Sprintf("must have property %q", name)
+				errs = append(errs, jsonSchemaError(v, node, pointer, reason))
+			}
+		}
+		for _, name := range sortedKeys(s.Properties) {
+			if property, ok := n[name]; ok {
+				propertyPointer := pointer + "/" + escapeJSONPointer(name)
+				errs = append(errs, checkJSONNode(v, s.Properties[name], property, propertyPointer))
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range n {
+				errs = append(errs, checkJSONNode(v, *s.Items, item, pointer+"/"+strconv.Itoa(i)))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func jsonSchemaError(v VarEntry, node any, pointer, reason string) *TypeError {
+	err := typeErrorWithReason(v, jsonText(node), reason)
+	err.Rule = "json_schema"
+	err.Pointer = pointer
+	return err
+}
+
+func jsonTypeMatches(jsonType string, node any) bool {
+	if f, ok := node.(float64); ok && jsonType == "integer" {
+		return f == math.Trunc(f)
+	}
+	return jsonTypeOf(node) == jsonType
+}
+
+// jsonTypeOf returns JSON type of node decoded by encoding/json, numbers are always "number"
+func jsonTypeOf(node any) string {
+	switch node.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
+
+/*
+jsonText encodes value as compact JSON, so values decoded from JSON and from YAML can be compared.
+Values which can not be encoded give "".
+*/
+func jsonText(value any) string {
+	var b strings.Builder
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func jsonTexts(values []any) []string {
+	texts := make([]string, len(values))
+	for i, value := range values {
+		texts[i] = jsonText(value)
+	}
+	return texts
+}
+
+// escapeJSONPointer escapes a reference token of JSON pointer as RFC 6901 requires
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkJSONOptions makes sure that only json vars have a JSON schema and that the schema is valid
+func checkJSONOptions(v VarEntry) error {
+	var message string
+	switch {
+	case v.JSONSchema != nil && v.Type != JSONType:
+		message = fmt.Sprintf("var %s has json_schema, but its type is %s", v.Name, v.Type)
+	case v.JSONSchema != nil:
+		if pointer, problem := checkJSONSchema(*v.JSONSchema, ""); problem != "" {
+			if pointer != "" {
+				problem = "at " + pointer + ": " + problem
+			}
+			message = fmt.Sprintf("var %s has invalid json_schema, %s", v.Name, problem)
+		}
+	}
+	if message == "" {
+		return nil
+	}
+
+	return &SchemaError{
+		ErrorDetails: ErrorDetails{Var: v.Name, Rule: "json_schema"},
+		Message:      message,
+	}
+}
+
+/*
+checkJSONSchema returns the first problem of schema s and JSON pointer of the schema node which
+has it, "" if there are none. pointer is the location of s in the whole schema.
+*/
+func checkJSONSchema(s JSONSchema, pointer string) (string, string) {
+	isObject := s.Type == "" || s.Type == "object"
+	isArray := s.Type == "" || s.Type == "array"
+
+	switch {
+	case s.Type != "" && !slices.Contains(jsonTypes, s.Type):
+		types := strings.Join(jsonTypes, ", ")
+		return pointer, fmt.Sprintf("type %q is not one of %s", s.Type, types)
+	case (len(s.Properties) > 0 || len(s.Required) > 0) && !isObject:
+		return pointer, "properties and required are only allowed for type object"
+	case s.Items != nil && !isArray:
+		return pointer, "items are only allowed for type array"
+	case slices.Contains(jsonTexts(s.Enum), ""):
+		return pointer, "enum has a value which can not be encoded as JSON"
+	}
+
+	for _, name := range sortedKeys(s.Properties) {
+		propertyPointer := pointer + "/properties/" + escapeJSONPointer(name)
+		if pointer, problem := checkJSONSchema(s.Properties[name], propertyPointer); problem != "" {
+			return pointer, problem
+		}
+	}
+	if s.Items != nil {
+		return checkJSONSchema(*s.Items, pointer+"/items")
+	}
+	return pointer, ""
+}
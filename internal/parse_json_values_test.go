@@ -0,0 +1,182 @@
+package internal_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+const featureConfigSchema = `
+vars:
+  - name: FEATURE_CONFIG
+    type: json
+    json_schema:
+      type: object
+      required: [mode, flags]
+      properties:
+        mode:
+          enum: [fast, safe, 1, {level: 2}]
+        flags:
+          type: array
+          items:
+            type: object
+            required: [name]
+            properties:
+              name:
+                type: string
+              rollout:
+                type: integer
+        a/b~c:
+          type: boolean
+`
+
+func readFeatureConfig(t *testing.T) internal.VarEntry {
+	t.Helper()
+
+	config, err := internal.ReadConfig(func(string) ([]byte, error) {
+		return []byte(featureConfigSchema), nil
+	}, ".env.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error \"%v\"", err)
+	}
+	return config.Vars[0]
+}
+
+func TestParseJSON(t *testing.T) {
+	v := readFeatureConfig(t)
+
+	testCases := []struct {
+		name     string
+		value    string
+		expected any
+	}{
+		{
+			name:  "Object",
+			value: `{"mode": "fast", "flags": [{"name": "beta", "rollout": 50}], "extra": null}`,
+			expected: map[string]any{
+				"mode":  "fast",
+				"flags": []any{map[string]any{"name": "beta", "rollout": 50.0}},
+				"extra": nil,
+			},
+		},
+		{
+			name:     "Number in enum",
+			value:    `{"mode": 1.0, "flags": []}`,
+			expected: map[string]any{"mode": 1.0, "flags": []any{}},
+		},
+		{
+			name:     "Object in enum",
+			value:    `{"mode": {"level": 2}, "flags": []}`,
+			expected: map[string]any{"mode": map[string]any{"level": 2.0}, "flags": []any{}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := internal.ParseValue(v, tc.value)
+			if err != nil {
+				t.Fatalf("unexpected error \"%v\"", err)
+			}
+			if !reflect.DeepEqual(parsed, tc.expected) {
+				t.Errorf("expected %#v, got %#v", tc.expected, parsed)
+			}
+		})
+	}
+
+	t.Run("Without schema", func(t *testing.T) {
+		v := internal.VarEntry{Name: "VAR", Type: internal.JSONType}
+		parsed, err := internal.ParseValue(v, `"text"`)
+		if err != nil || parsed != "text" {
+			t.Errorf("expected \"text\", got %v and error \"%v\"", parsed, err)
+		}
+	})
+}
+
+func TestParseJSONErrors(t *testing.T) {
+	v := readFeatureConfig(t)
+
+	testCases := []struct {
+		name               string
+		value              string
+		expectedErrorTexts []string
+	}{
+		{
+			name:  "Invalid JSON",
+			value: `{"mode": "fast",}`,
+			expectedErrorTexts: []string{
+				"var FEATURE_CONFIG is not a valid json: must be valid JSON, invalid character '}'",
+			},
+		},
+		{
+			name:  "Wrong root type",
+			value: `[]`,
+			expectedErrorTexts: []string{
+				"var FEATURE_CONFIG is not a valid json: must be of type object, got array",
+			},
+		},
+		{
+			name:  "Missing properties",
+			value: `{}`,
+			expectedErrorTexts: []string{
+				"var FEATURE_CONFIG is not a valid json: must have property \"mode\"",
+				"var FEATURE_CONFIG is not a valid json: must have property \"flags\"",
+			},
+		},
+		{
+			name:  "Nested nodes",
+			value: `{"mode": "slow", "flags": [{"name": "a"}, {"rollout": 0.5}], "a/b~c": 1}`,
+			expectedErrorTexts: []string{
+				"var FEATURE_CONFIG at /mode is not a valid json: " +
+					"must be one of \"fast\", \"safe\", 1, {\"level\":2}",
+				"var FEATURE_CONFIG at /flags/1 is not a valid json: must have property \"name\"",
+				"var FEATURE_CONFIG at /flags/1/rollout is not a valid json: " +
+					"must be of type integer, got number",
+				"var FEATURE_CONFIG at /a~1b~0c is not a valid json: " +
+					"must be of type boolean, got number",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := internal.ParseValue(v, tc.value)
+
+			var typeErr *internal.TypeError
+			if !errors.As(err, &typeErr) {
+				t.Fatalf("expected TypeError, got \"%v\"", err)
+			}
+			for _, expected := range tc.expectedErrorTexts {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected \"%s\" to contain \"%s\"", err.Error(), expected)
+				}
+			}
+		})
+	}
+
+	t.Run("Pointer is reported in details", func(t *testing.T) {
+		_, err := internal.ParseValue(v, `{"mode": "fast", "flags": [{"name": false}]}`)
+
+		var typeErr *internal.TypeError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("expected TypeError, got \"%v\"", err)
+		}
+		details := typeErr.Details()
+		if details.Pointer != "/flags/0/name" || details.Rule != "json_schema" ||
+			details.Value != "false" {
+			t.Errorf("unexpected details %+v", details)
+		}
+	})
+
+	t.Run("Secret value", func(t *testing.T) {
+		secret := internal.VarEntry{Name: "VAR", Type: internal.JSONType, Secret: true}
+		_, err := internal.ParseValue(secret, `{"token": hunter2}`)
+		if err == nil || strings.Contains(err.Error(), "hunter2") ||
+			strings.Contains(err.Error(), "'h'") {
+			t.Errorf("expected error without the value, got \"%v\"", err)
+		}
+	})
+
+}
@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// HostPort is the value of a hostport var
+type HostPort struct {
+	Host string
+	Port int
+}
+
+// String returns hp as host:port, with brackets around IPv6 hosts
+func (hp HostPort) String() string {
+	return net.JoinHostPort(hp.Host, strconv.Itoa(hp.Port))
+}
+
+const portReason = "must be a number from 1 to 65535"
+
+/*
+parseURL parses an absolute URL. Its scheme must be one of v.Schemes if they are set, compared
+case-insensitively, and it must have a host if v.RequireHost is set.
+*/
+func parseURL(v VarEntry, value string) (*url.URL, error) {
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, typeErrorWithReason(v, value, "can not be parsed")
+	}
+	if u.Scheme == "" {
+		return nil, typeErrorWithReason(v, value, "must be absolute, with a scheme")
+	}
+	if len(v.Schemes) > 0 && !slices.ContainsFunc(v.Schemes, func(scheme string) bool {
+		return strings.EqualFold(scheme, u.Scheme)
+	}) {
+		return nil, typeErrorWithReason(v, value,
+			"scheme must be one of "+strings.Join(v.Schemes, ", "))
+	}
+	if v.RequireHost && u.Hostname() == "" {
+		return nil, typeErrorWithReason(v, value, "must have a host")
+	}
+	return u, nil
+}
+
+// parseHostPort parses host:port, IPv6 hosts must be in brackets like [::1]:80
+func parseHostPort(v VarEntry, value string) (HostPort, error) {
+	host, portValue, err := net.SplitHostPort(value)
+	if err != nil {
+		return HostPort{}, typeErrorWithReason(v, value, "must be host:port")
+	}
+	if host == "" {
+		return HostPort{}, typeErrorWithReason(v, value, "host must not be empty")
+	}
+	port, ok := parsePortNumber(portValue)
+	if !ok {
+		return HostPort{}, typeErrorWithReason(v, value, "port "+portReason)
+	}
+	return HostPort{Host: host, Port: port}, nil
+}
+
+// parseIP parses an IP address of version v.IPVersion
+func parseIP(v VarEntry, value string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(value)
+	if err != nil || !matchesIPVersion(addr, v.IPVersion) {
+		return netip.Addr{}, typeErrorWithReason(v, value, "must be "+ipDescription(v.IPVersion))
+	}
+	return addr, nil
+}
+
+// parseCIDR parses an IP network like 10.0.0.0/8 of version v.IPVersion
+func parseCIDR(v VarEntry, value string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil || !matchesIPVersion(prefix.Addr(), v.IPVersion) {
+		return netip.Prefix{}, typeErrorWithReason(v, value,
+			"must be "+ipDescription(v.IPVersion)+" with a prefix length, like "+
+				cidrExample(v.IPVersion))
+	}
+	return prefix, nil
+}
+
+func parsePort(v VarEntry, value string) (int, error) {
+	port, ok := parsePortNumber(value)
+	if !ok {
+		return 0, typeErrorWithReason(v, value, portReason)
+	}
+	return port, nil
+}
+
+func parsePortNumber(value string) (int, bool) {
+	port, err := strconv.Atoi(value)
+	return port, err == nil && port >= 1 && port <= 65535
+}
+
+func matchesIPVersion(addr netip.Addr, version int) bool {
+	switch version {
+	case 4:
+		return addr.Is4()
+	case 6:
+		return addr.Is6()
+	default:
+		return true
+	}
+}
+
+func ipDescription(version int) string {
+	switch version {
+	case 4:
+		return "an IPv4 address"
+	case 6:
+		return "an IPv6 address"
+	default:
+		return "an IPv4 or IPv6 address"
+	}
+}
+
+func cidrExample(version int) string {
+	if version == 6 {
+		return "fd00::/8"
+	}
+	return "10.0.0.0/8"
+}
+
+/*
+checkNetworkOptions makes sure that options of network types are only set for the types which use
+them and have valid values.
+*/
+func checkNetworkOptions(v VarEntry) error {
+	var message string
+	switch {
+	case (len(v.Schemes) > 0 || v.RequireHost) && v.Type != URLType:
+		message = fmt.Sprintf("var %s has url options, but its type is %s", v.Name, v.Type)
+	case v.IPVersion != 0 && v.Type != IPType && v.Type != CIDRType:
+		message = fmt.Sprintf("var %s has ip_version, but its type is %s", v.Name, v.Type)
+	case v.IPVersion != 0 && v.IPVersion != 4 && v.IPVersion != 6:
+		message = fmt.Sprintf("var %s has ip_version %d, expected 4 or 6", v.Name, v.IPVersion)
+	default:
+		return nil
+	}
+
+	return &SchemaError{
+		ErrorDetails: ErrorDetails{Var: v.Name, Rule: "type"},
+		Message:      message,
+	}
+}
@@ -0,0 +1,208 @@
+package internal_test
+
+import (
+	"errors"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestParseNetworkValues(t *testing.T) {
+	testCases := []struct {
+		name     string
+		v        internal.VarEntry
+		value    string
+		expected any
+	}{
+		{
+			name:     "URL",
+			v:        internal.VarEntry{Type: internal.URLType},
+			value:    "postgres://user:pass@db:5432/app?sslmode=disable",
+			expected: "postgres://user:pass@db:5432/app?sslmode=disable",
+		},
+		{
+			name:     "URL with allowed scheme in other case",
+			v:        internal.VarEntry{Type: internal.URLType, Schemes: []string{"http", "https"}},
+			value:    "HTTPS://example.com",
+			expected: "https://example.com",
+		},
+		{
+			name:     "URL without host",
+			v:        internal.VarEntry{Type: internal.URLType},
+			value:    "sqlite:///data/app.db",
+			expected: "sqlite:///data/app.db",
+		},
+		{
+			name:     "Host and port",
+			v:        internal.VarEntry{Type: internal.HostPortType},
+			value:    "db.local:5432",
+			expected: internal.HostPort{Host: "db.local", Port: 5432},
+		},
+		{
+			name:     "IPv6 host and port",
+			v:        internal.VarEntry{Type: internal.HostPortType},
+			value:    "[::1]:80",
+			expected: internal.HostPort{Host: "::1", Port: 80},
+		},
+		{
+			name:     "IPv4",
+			v:        internal.VarEntry{Type: internal.IPType, IPVersion: 4},
+			value:    "192.168.0.1",
+			expected: netip.MustParseAddr("192.168.0.1"),
+		},
+		{
+			name:     "IPv6",
+			v:        internal.VarEntry{Type: internal.IPType},
+			value:    "2001:db8::1",
+			expected: netip.MustParseAddr("2001:db8::1"),
+		},
+		{
+			name:     "CIDR",
+			v:        internal.VarEntry{Type: internal.CIDRType, IPVersion: 6},
+			value:    "fd00::/8",
+			expected: netip.MustParsePrefix("fd00::/8"),
+		},
+		{
+			name:     "Port",
+			v:        internal.VarEntry{Type: internal.PortType},
+			value:    "65535",
+			expected: 65535,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.v.Name = "VAR"
+			parsed, err := internal.ParseValue(tc.v, tc.value)
+			if err != nil {
+				t.Fatalf("unexpected error \"%v\"", err)
+			}
+
+			if u, ok := parsed.(*url.URL); ok {
+				// Schemes are compared without case, but the parsed one is lowercase
+				parsed = u.String()
+			}
+			if parsed != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, parsed)
+			}
+		})
+	}
+}
+
+func TestParseNetworkValuesErrors(t *testing.T) {
+	testCases := []struct {
+		name              string
+		v                 internal.VarEntry
+		value             string
+		expectedErrorText string
+	}{
+		{
+			name:              "Relative URL",
+			v:                 internal.VarEntry{Type: internal.URLType},
+			value:             "localhost/path",
+			expectedErrorText: "var VAR is not a valid url: must be absolute, with a scheme",
+		},
+		{
+			name:              "URL which can not be parsed",
+			v:                 internal.VarEntry{Type: internal.URLType},
+			value:             "http://[::1",
+			expectedErrorText: "var VAR is not a valid url: can not be parsed",
+		},
+		{
+			name: "URL scheme is not allowed",
+			v: internal.VarEntry{
+				Type:    internal.URLType,
+				Schemes: []string{"http", "https"},
+			},
+			value:             "ftp://example.com",
+			expectedErrorText: "var VAR is not a valid url: scheme must be one of http, https",
+		},
+		{
+			name:              "URL without required host",
+			v:                 internal.VarEntry{Type: internal.URLType, RequireHost: true},
+			value:             "file:///etc/hosts",
+			expectedErrorText: "var VAR is not a valid url: must have a host",
+		},
+		{
+			name:              "Host without port",
+			v:                 internal.VarEntry{Type: internal.HostPortType},
+			value:             "db.local",
+			expectedErrorText: "var VAR is not a valid hostport: must be host:port",
+		},
+		{
+			name:              "Empty host",
+			v:                 internal.VarEntry{Type: internal.HostPortType},
+			value:             ":8080",
+			expectedErrorText: "var VAR is not a valid hostport: host must not be empty",
+		},
+		{
+			name:  "Port of host out of range",
+			v:     internal.VarEntry{Type: internal.HostPortType},
+			value: "db.local:0",
+			expectedErrorText: "var VAR is not a valid hostport: " +
+				"port must be a number from 1 to 65535",
+		},
+		{
+			name:              "Invalid IP",
+			v:                 internal.VarEntry{Type: internal.IPType},
+			value:             "256.0.0.1",
+			expectedErrorText: "var VAR is not a valid ip: must be an IPv4 or IPv6 address",
+		},
+		{
+			name:              "IPv6 instead of IPv4",
+			v:                 internal.VarEntry{Type: internal.IPType, IPVersion: 4},
+			value:             "::1",
+			expectedErrorText: "var VAR is not a valid ip: must be an IPv4 address",
+		},
+		{
+			name:  "CIDR without prefix length",
+			v:     internal.VarEntry{Type: internal.CIDRType},
+			value: "10.0.0.0",
+			expectedErrorText: "must be an IPv4 or IPv6 address with a prefix length, " +
+				"like 10.0.0.0/8",
+		},
+		{
+			name:              "Port out of range",
+			v:                 internal.VarEntry{Type: internal.PortType},
+			value:             "99999",
+			expectedErrorText: "var VAR is not a valid port: must be a number from 1 to 65535",
+		},
+		{
+			name:              "Port is not a number",
+			v:                 internal.VarEntry{Type: internal.PortType},
+			value:             "http",
+			expectedErrorText: "var VAR is not a valid port: must be a number from 1 to 65535",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.v.Name = "VAR"
+			_, err := internal.ParseValue(tc.v, tc.value)
+
+			var typeErr *internal.TypeError
+			if !errors.As(err, &typeErr) {
+				t.Fatalf("expected TypeError, got \"%v\"", err)
+			}
+			if typeErr.Value != tc.value {
+				t.Errorf("expected value \"%s\", got \"%s\"", tc.value, typeErr.Value)
+			}
+			if !strings.Contains(err.Error(), tc.expectedErrorText) {
+				t.Errorf("expected \"%s\" to contain \"%s\"", err.Error(), tc.expectedErrorText)
+			}
+		})
+	}
+
+	t.Run("Secret value is redacted", func(t *testing.T) {
+		v := internal.VarEntry{Name: "VAR", Type: internal.URLType, Secret: true}
+		_, err := internal.ParseValue(v, "token-without-scheme")
+
+		var typeErr *internal.TypeError
+		if !errors.As(err, &typeErr) || typeErr.Value != internal.RedactedValue {
+			t.Errorf("expected redacted TypeError, got \"%v\"", err)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Bytes in a unit of size, keyed by lowercase unit. Units may be written in any case.
+var sizeUnits = map[string]int64{
+	"": 1, "b": 1,
+	"k": 1e3, "kb": 1e3,
+	"m": 1e6, "mb": 1e6,
+	"g": 1e9, "gb": 1e9,
+	"t": 1e12, "tb": 1e12,
+	"p": 1e15, "pb": 1e15,
+	"e": 1e18, "eb": 1e18,
+	"ki": 1 << 10, "kib": 1 << 10,
+	"mi": 1 << 20, "mib": 1 << 20,
+	"gi": 1 << 30, "gib": 1 << 30,
+	"ti": 1 << 40, "tib": 1 << 40,
+	"pi": 1 << 50, "pib": 1 << 50,
+	"ei": 1 << 60, "eib": 1 << 60,
+}
+
+// Number with an optional unit, maybe separated by spaces
+var sizeSyntax = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z]*)$`)
+
+// Number in decimal or exponent notation with an optional unit
+var quantitySyntax = regexp.MustCompile(
+	`^([+-]?[0-9]+(?:\.[0-9]+)?(?:[eE][+-]?[0-9]+)?)\s*(.*)$`,
+)
+
+// parseSize parses a size like 512MiB or 10MB to bytes, which must be within v.Min and v.Max
+func parseSize(v VarEntry, value string) (int64, error) {
+	size, err := parseByteSize(value)
+	if err != nil {
+		return 0, typeErrorWithReason(v, value, err.Error())
+	}
+	if reason := checkBounds(v, size, parseByteSize); reason != "" {
+		return 0, typeErrorWithReason(v, value, reason)
+	}
+	return size, nil
+}
+
+/*
+parseByteSize parses a number of bytes with an optional SI unit (kB, MB, GB, TB, PB, EB, powers
+of 1000) or IEC unit (KiB, MiB, GiB, TiB, PiB, EiB, powers of 1024). Units may be shortened to
+k, M, Gi and so on. Fractions are allowed if they give a whole number of bytes, like 1.5kB.
+*/
+func parseByteSize(value string) (int64, error) {
+	match := sizeSyntax.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, errors.New("must be a number of bytes with an optional unit like 512MiB or 10MB")
+	}
+
+	multiplier, ok := sizeUnits[strings.ToLower(match[2])]
+	if !ok {
+		return 0, fmt.Errorf("unit %s is unknown, use B, kB, MB, GB, TB, PB, EB or "+
+			"KiB, MiB, GiB, TiB, PiB, EiB", match[2])
+	}
+
+	size, _ := new(big.Rat).SetString(match[1])
+	size.Mul(size, new(big.Rat).SetInt64(multiplier))
+	if !size.IsInt() {
+		return 0, errors.New("must be a whole number of bytes")
+	}
+	if !size.Num().IsInt64() {
+		return 0, errors.New("is too large")
+	}
+	return size.Num().Int64(), nil
+}
+
+/*
+parseQuantityValue parses a number with one of the units of quantity var v and returns it in
+v.Unit, which must be within v.Min and v.Max.
+*/
+func parseQuantityValue(v VarEntry, value string) (float64, error) {
+	quantity, err := parseQuantity(v, value, true)
+	if err != nil {
+		return 0, typeErrorWithReason(v, value, err.Error())
+	}
+
+	bound := func(bound string) (float64, error) {
+		return parseQuantity(v, bound, false)
+	}
+	if reason := checkBounds(v, quantity, bound); reason != "" {
+		return 0, typeErrorWithReason(v, value, reason)
+	}
+	return quantity, nil
+}
+
+/*
+parseQuantity parses a number with one of the units of quantity var v and converts it to v.Unit.
+A number without a unit is taken in v.Unit unless unitRequired is set.
+*/
+func parseQuantity(v VarEntry, value string, unitRequired bool) (float64, error) {
+	units := strings.Join(quantityUnits(v), ", ")
+
+	match := quantitySyntax.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("must be a number with a unit like 10%s", v.Unit)
+	}
+
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, errors.New("number is out of range")
+	}
+
+	var factor float64
+	switch unit := match[2]; {
+	case unit == "" && unitRequired:
+		return 0, fmt.Errorf("must have a unit: %s", units)
+	case unit == "" || unit == v.Unit:
+		factor = 1
+	default:
+		var ok bool
+		if factor, ok = v.Units[unit]; !ok {
+			return 0, fmt.Errorf("unit %s is not one of %s", unit, units)
+		}
+	}
+	return number * factor, nil
+}
+
+// quantityUnits returns units of quantity var v, v.Unit first and the others sorted
+func quantityUnits(v VarEntry) []string {
+	units := []string{v.Unit}
+	for unit := range v.Units {
+		if unit != v.Unit {
+			units = append(units, unit)
+		}
+	}
+	slices.Sort(units[1:])
+	return units
+}
+
+/*
+checkQuantityOptions makes sure that units are only set for quantity vars, and that every
+quantity var has a unit and positive factors of its other units.
+*/
+func checkQuantityOptions(v VarEntry) error {
+	var message string
+	switch {
+	case (v.Unit != "" || len(v.Units) > 0) && v.Type != QuantityType:
+		message = fmt.Sprintf("var %s has units, but its type is %s", v.Name, v.Type)
+	case v.Type == QuantityType && v.Unit == "":
+		message = fmt.Sprintf("var %s has type quantity, but its unit is not set", v.Name)
+	case v.Type == QuantityType:
+		for _, unit := range quantityUnits(v)[1:] {
+			if unit == "" || v.Units[unit] <= 0 {
+				message = fmt.Sprintf("var %s has unit %q, which must be a positive number of %s",
+					v.Name, unit, v.Unit)
+				break
+			}
+		}
+	}
+	if message == "" {
+		return nil
+	}
+
+	return &SchemaError{
+		ErrorDetails: ErrorDetails{Var: v.Name, Rule: "type"},
+		Message:      message,
+	}
+}
@@ -0,0 +1,179 @@
+package internal_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestParseSize(t *testing.T) {
+	testCases := []struct {
+		value             string
+		expected          int64
+		expectedErrorText string
+	}{
+		{"1024", 1024, ""},
+		{"0", 0, ""},
+		{"100B", 100, ""},
+		{"10MB", 10_000_000, ""},
+		{"10mb", 10_000_000, ""},
+		{"1.5kB", 1500, ""},
+		{"512MiB", 512 << 20, ""},
+		{"2 GiB", 2 << 30, ""},
+		{"4Gi", 4 << 30, ""},
+		{"1K", 1000, ""},
+		{"8EiB", 0, "is too large"}, // does not fit int64
+		{"7EiB", 7 << 60, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			v := internal.VarEntry{Name: "VAR", Type: internal.SizeType}
+			parsed, err := internal.ParseValue(v, tc.value)
+			if tc.expectedErrorText != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErrorText) {
+					t.Fatalf("expected error \"%s\", got \"%v\"", tc.expectedErrorText, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error \"%v\"", err)
+			}
+			if parsed != tc.expected {
+				t.Errorf("expected %d, got %v", tc.expected, parsed)
+			}
+		})
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	rate := internal.VarEntry{
+		Name:  "VAR",
+		Type:  internal.QuantityType,
+		Unit:  "rps",
+		Units: map[string]float64{"rpm": 1.0 / 60, "krps": 1000},
+		Max:   "2000",
+	}
+	percent := internal.VarEntry{Name: "VAR", Type: internal.QuantityType, Unit: "%", Min: "0"}
+
+	testCases := []struct {
+		name     string
+		v        internal.VarEntry
+		value    string
+		expected float64
+	}{
+		{"Declared unit", rate, "150rps", 150},
+		{"Other unit", rate, "120 rpm", 2},
+		{"Exponent", rate, "1.5e0krps", 1500},
+		{"Percent", percent, "12.5%", 12.5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := internal.ParseValue(tc.v, tc.value)
+			if err != nil {
+				t.Fatalf("unexpected error \"%v\"", err)
+			}
+			if parsed != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, parsed)
+			}
+		})
+	}
+}
+
+func TestParseQuantityValuesErrors(t *testing.T) {
+	rate := internal.VarEntry{
+		Type:  internal.QuantityType,
+		Unit:  "rps",
+		Units: map[string]float64{"rpm": 1.0 / 60},
+		Min:   "1",
+		Max:   "60rpm",
+	}
+
+	testCases := []struct {
+		name              string
+		v                 internal.VarEntry
+		value             string
+		expectedErrorText string
+	}{
+		{
+			name:              "Size which is not a number",
+			v:                 internal.VarEntry{Type: internal.SizeType},
+			value:             "lots",
+			expectedErrorText: "var VAR is not a valid size: must be a number of bytes",
+		},
+		{
+			name:              "Negative size",
+			v:                 internal.VarEntry{Type: internal.SizeType},
+			value:             "-1MB",
+			expectedErrorText: "var VAR is not a valid size: must be a number of bytes",
+		},
+		{
+			name:              "Unknown size unit",
+			v:                 internal.VarEntry{Type: internal.SizeType},
+			value:             "10XB",
+			expectedErrorText: "var VAR is not a valid size: unit XB is unknown",
+		},
+		{
+			name:              "Fraction of byte",
+			v:                 internal.VarEntry{Type: internal.SizeType},
+			value:             "1.5B",
+			expectedErrorText: "var VAR is not a valid size: must be a whole number of bytes",
+		},
+		{
+			name:              "Size above max",
+			v:                 internal.VarEntry{Type: internal.SizeType, Max: "1GiB"},
+			value:             "2GB",
+			expectedErrorText: "var VAR is not a valid size: must be at most 1GiB",
+		},
+		{
+			name:              "Quantity without unit",
+			v:                 rate,
+			value:             "10",
+			expectedErrorText: "var VAR is not a valid quantity: must have a unit: rps, rpm",
+		},
+		{
+			name:              "Quantity with unknown unit",
+			v:                 rate,
+			value:             "10rph",
+			expectedErrorText: "var VAR is not a valid quantity: unit rph is not one of rps, rpm",
+		},
+		{
+			name:              "Quantity below min",
+			v:                 rate,
+			value:             "30rpm",
+			expectedErrorText: "var VAR is not a valid quantity: must be at least 1rps",
+		},
+		{
+			name:              "Quantity above max",
+			v:                 rate,
+			value:             "2rps",
+			expectedErrorText: "var VAR is not a valid quantity: must be at most 60rpm",
+		},
+		{
+			name:  "Quantity which is not a number",
+			v:     rate,
+			value: "fast",
+			expectedErrorText: "var VAR is not a valid quantity: " +
+				"must be a number with a unit like 10rps",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.v.Name = "VAR"
+			_, err := internal.ParseValue(tc.v, tc.value)
+
+			var typeErr *internal.TypeError
+			if !errors.As(err, &typeErr) {
+				t.Fatalf("expected TypeError, got \"%v\"", err)
+			}
+			if !strings.Contains(err.Error(), tc.expectedErrorText) {
+				t.Errorf("expected \"%s\" to contain \"%s\"", err.Error(), tc.expectedErrorText)
+			}
+		})
+	}
+
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+)
+
+// parseDuration parses a duration like 1h30m, which must be within v.Min and v.Max if they are set
+func parseDuration(v VarEntry, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, typeErrorWithReason(v, value, "must be a duration like 30s or 1h30m")
+	}
+
+	if reason := checkBounds(v, d, time.ParseDuration); reason != "" {
+		return 0, typeErrorWithReason(v, value, reason)
+	}
+	return d, nil
+}
+
+// parseTime parses a point in time in v.Layout, RFC 3339 by default
+func parseTime(v VarEntry, value string) (time.Time, error) {
+	layout := v.Layout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, typeErrorWithReason(v, value, "must be a time in format "+layout)
+	}
+	return t, nil
+}
+
+/*
+parseTimezone loads the location of an IANA time zone name. Names are resolved with the time zone
+database of the system, so they are valid only where the application can load them as well.
+*/
+func parseTimezone(v VarEntry, value string) (*time.Location, error) {
+	// LoadLocation takes "" for UTC and "Local" for the zone of the machine, they are not names
+	if value == "" || value == "Local" {
+		return nil, typeErrorWithReason(v, value, timezoneReason)
+	}
+
+	location, err := time.LoadLocation(value)
+	if err != nil {
+		return nil, typeErrorWithReason(v, value, timezoneReason)
+	}
+	return location, nil
+}
+
+const timezoneReason = "must be an IANA time zone name like Europe/Berlin"
+
+// checkTimeOptions makes sure that layout is only set for time vars
+func checkTimeOptions(v VarEntry) error {
+	if v.Layout == "" || v.Type == TimeType {
+		return nil
+	}
+
+	return &SchemaError{
+		ErrorDetails: ErrorDetails{Var: v.Name, Rule: "type"},
+		Message:      fmt.Sprintf("var %s has layout, but its type is %s", v.Name, v.Type),
+	}
+}
@@ -0,0 +1,127 @@
+package internal_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestParseTimeValues(t *testing.T) {
+	t.Run("Duration", func(t *testing.T) {
+		v := internal.VarEntry{Name: "VAR", Type: internal.DurationType, Min: "1s", Max: "1h"}
+		parsed, err := internal.ParseValue(v, "1m30s")
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		if parsed != 90*time.Second {
+			t.Errorf("expected 1m30s, got %v", parsed)
+		}
+	})
+
+	t.Run("Time in RFC 3339", func(t *testing.T) {
+		v := internal.VarEntry{Name: "VAR", Type: internal.TimeType}
+		parsed, err := internal.ParseValue(v, "2024-02-29T12:30:00+03:00")
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		expected := time.Date(2024, 2, 29, 9, 30, 0, 0, time.UTC)
+		if tm, ok := parsed.(time.Time); !ok || !tm.Equal(expected) {
+			t.Errorf("expected %v, got %v", expected, parsed)
+		}
+	})
+
+	t.Run("Time in custom layout", func(t *testing.T) {
+		v := internal.VarEntry{Name: "VAR", Type: internal.TimeType, Layout: "2006-01-02"}
+		parsed, err := internal.ParseValue(v, "2024-02-29")
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		if parsed != time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC) {
+			t.Errorf("expected 2024-02-29, got %v", parsed)
+		}
+	})
+
+	t.Run("Timezone", func(t *testing.T) {
+		v := internal.VarEntry{Name: "VAR", Type: internal.TimezoneType}
+		parsed, err := internal.ParseValue(v, "UTC")
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		if location, ok := parsed.(*time.Location); !ok || location.String() != "UTC" {
+			t.Errorf("expected UTC, got %v", parsed)
+		}
+	})
+}
+
+func TestParseTimeValuesErrors(t *testing.T) {
+	testCases := []struct {
+		name              string
+		v                 internal.VarEntry
+		value             string
+		expectedErrorText string
+	}{
+		{
+			name:  "Duration without unit",
+			v:     internal.VarEntry{Type: internal.DurationType},
+			value: "30",
+			expectedErrorText: "var VAR is not a valid duration: " +
+				"must be a duration like 30s or 1h30m",
+		},
+		{
+			name:              "Duration below min",
+			v:                 internal.VarEntry{Type: internal.DurationType, Min: "1s"},
+			value:             "500ms",
+			expectedErrorText: "var VAR is not a valid duration: must be at least 1s",
+		},
+		{
+			name:              "Duration above max",
+			v:                 internal.VarEntry{Type: internal.DurationType, Max: "1m"},
+			value:             "2m",
+			expectedErrorText: "var VAR is not a valid duration: must be at most 1m",
+		},
+		{
+			name:  "Time not in RFC 3339",
+			v:     internal.VarEntry{Type: internal.TimeType},
+			value: "2024-02-29 12:30",
+			expectedErrorText: "var VAR is not a valid time: " +
+				"must be a time in format " + time.RFC3339,
+		},
+		{
+			name:              "Time not in custom layout",
+			v:                 internal.VarEntry{Type: internal.TimeType, Layout: "2006-01-02"},
+			value:             "2023-02-29",
+			expectedErrorText: "var VAR is not a valid time: must be a time in format 2006-01-02",
+		},
+		{
+			name:              "Unknown timezone",
+			v:                 internal.VarEntry{Type: internal.TimezoneType},
+			value:             "Mars/Olympus_Mons",
+			expectedErrorText: "var VAR is not a valid timezone: must be an IANA time zone name",
+		},
+		{
+			name:              "Local is not a timezone name",
+			v:                 internal.VarEntry{Type: internal.TimezoneType},
+			value:             "Local",
+			expectedErrorText: "var VAR is not a valid timezone: must be an IANA time zone name",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.v.Name = "VAR"
+			_, err := internal.ParseValue(tc.v, tc.value)
+
+			var typeErr *internal.TypeError
+			if !errors.As(err, &typeErr) {
+				t.Fatalf("expected TypeError, got \"%v\"", err)
+			}
+			if !strings.Contains(err.Error(), tc.expectedErrorText) {
+				t.Errorf("expected \"%s\" to contain \"%s\"", err.Error(), tc.expectedErrorText)
+			}
+		})
+	}
+
+}
@@ -1,30 +1,33 @@
 package internal
 
 import (
-	"errors"
 	"fmt"
-	"os"
 )
 
 /*
-This function iterates through c["vars"] and for each var that is not set, it populates the var with its default value. "Populate" means to set os.env[var.Name].
+This function iterates through c["vars"] and for each var that is not set, it populates the var
+with its default value. "Populate" means to set env[var.Name].
 */
-func PopulateUnsetVarsWithDefaults(c *Config) error {
-	var populationError error = nil
-
+func PopulateUnsetVarsWithDefaults(c *Config, env map[string]string) {
 	for _, v := range c.Vars {
-		_, isSet := os.LookupEnv(v.Name)
+		_, isSet := env[v.Name]
 		if !isSet {
 			if v.DefaultValue != nil {
-				err := os.Setenv(v.Name, fmt.Sprintf("%v", v.DefaultValue))
-				if err != nil {
-					populationError = errors.Join(
-						errors.Join(errors.New("failed to set env var"), err),
-						populationError,
-					)
-				}
+				env[v.Name] = defaultValueString(v)
 			}
 		}
 	}
-	return populationError
+}
+
+/*
+defaultValueString formats default value of v as it is written to env. Defaults of json vars which
+are not strings, like YAML mappings, are encoded as JSON.
+*/
+func defaultValueString(v VarEntry) string {
+	if _, isString := v.DefaultValue.(string); v.Type == JSONType && !isString {
+		if text := jsonText(v.DefaultValue); text != "" {
+			return text
+		}
+	}
+	return fmt.Sprintf("%v", v.DefaultValue)
 }
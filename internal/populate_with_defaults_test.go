@@ -2,36 +2,11 @@ package internal_test
 
 import (
 	"fmt"
-	"os"
 	"testing"
 
 	"github.com/jogang0304/envcheck/internal"
 )
 
-func saveAndClearEnv(keys []string) map[string]*string {
-	original := make(map[string]*string)
-	for _, key := range keys {
-		if val, ok := os.LookupEnv(key); ok {
-			v := val // копия строки
-			original[key] = &v
-			_ = os.Unsetenv(key)
-		} else {
-			original[key] = nil
-		}
-	}
-	return original
-}
-
-func restoreEnv(env map[string]*string) {
-	for key, val := range env {
-		if val == nil {
-			_ = os.Unsetenv(key)
-		} else {
-			_ = os.Setenv(key, *val)
-		}
-	}
-}
-
 func TestPopulateUnsetRequiredVarsWithDefaults(t *testing.T) {
 	// Define a test case
 	testCases := []struct {
@@ -110,31 +85,38 @@ func TestPopulateUnsetRequiredVarsWithDefaults(t *testing.T) {
 				"SOME_VAR":    "default_some",
 			},
 		},
+		{
+			name: "Json vars have defaults encoded as JSON",
+			config: internal.Config{
+				Vars: []internal.VarEntry{
+					{
+						Name:         "FEATURE_CONFIG",
+						Type:         internal.JSONType,
+						DefaultValue: map[string]any{"mode": "safe", "flags": []any{"a<b"}},
+					},
+					{Name: "RAW_CONFIG", Type: internal.JSONType, DefaultValue: `{"mode": "fast"}`},
+				},
+			},
+			presetVars: map[string]any{},
+			expectedResult: map[string]any{
+				"FEATURE_CONFIG": `{"flags":["a<b"],"mode":"safe"}`,
+				"RAW_CONFIG":     `{"mode": "fast"}`,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			var keys []string
-			for _, v := range tc.config.Vars {
-				keys = append(keys, v.Name)
-			}
-			// Save and clear the environment variables
-			originalEnv := saveAndClearEnv(keys)
-			defer restoreEnv(originalEnv)
-
-			// Set preset environment variables
+			env := make(map[string]string)
 			for key, value := range tc.presetVars {
-				t.Setenv(key, fmt.Sprintf("%v", value))
+				env[key] = fmt.Sprintf("%v", value)
 			}
 
-			err := internal.PopulateUnsetVarsWithDefaults(&tc.config)
-			if err != nil {
-				t.Fatalf("failed to populate unset vars with defaults: %v", err)
-			}
+			internal.PopulateUnsetVarsWithDefaults(&tc.config, env)
 
-			// Check if the environment variables are set correctly
+			// Check if the vars are set correctly
 			for v := range tc.expectedResult {
-				value, exists := os.LookupEnv(v)
+				value, exists := env[v]
 				if !exists && tc.expectedResult[v] != nil {
 					t.Errorf("environment variable %s is not set", v)
 					continue
@@ -0,0 +1,234 @@
+package internal
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// DocsFormat is the output format of RenderDocs
+type DocsFormat string
+
+const (
+	MarkdownDocs DocsFormat = "markdown"
+	HTMLDocs     DocsFormat = "html"
+)
+
+// DefaultDocsGroup is the section of vars which do not have a group
+const DefaultDocsGroup = "General"
+
+// Shown instead of default values of secret vars
+const hiddenSecretDefault = "(secret)"
+
+// docsGroup is a section of the documentation
+type docsGroup struct {
+	Name string
+	Vars []docsVar
+}
+
+// docsVar holds values of a var as they are shown in the documentation
+type docsVar struct {
+	Name     string
+	Type     string
+	Required bool
+	Default  string
+	// Default value is set, but hidden because the var is secret
+	HiddenDefault bool
+	Pattern       string
+	AllowedValues []string
+	Description   string
+}
+
+/*
+This function renders documentation of the vars declared in c as a Markdown page or a standalone
+HTML page with the given title. Every var gets its name, type, whether it is required, default
+value, pattern, allowed values and description. Vars are split into sections by VarEntry.Group, in
+order of the first appearance of each group; vars without a group go to DefaultDocsGroup. If no var
+has a group, the vars are listed in one table without sections.
+
+Default values of secret vars are never printed.
+*/
+func RenderDocs(c *Config, format DocsFormat, title string) (string, error) {
+	groups := docsGroups(c)
+
+	switch format {
+	case MarkdownDocs:
+		return renderMarkdown(groups, title), nil
+	case HTMLDocs:
+		var b strings.Builder
+		err := htmlDocsTemplate.Execute(&b, struct {
+			Title  string
+			Groups []docsGroup
+		}{title, groups})
+		if err != nil {
+			return "", fmt.Errorf("failed to render html\n\t%w", err)
+		}
+		return b.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported docs format %q", format)
+	}
+}
+
+// docsGroups splits vars of c into sections, a single section without name if there are no groups
+func docsGroups(c *Config) []docsGroup {
+	var groups []docsGroup
+	index := make(map[string]int)
+	hasGroups := false
+
+	for _, v := range c.Vars {
+		name := v.Group
+		if name == "" {
+			name = DefaultDocsGroup
+		} else {
+			hasGroups = true
+		}
+
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, docsGroup{Name: name})
+		}
+		groups[i].Vars = append(groups[i].Vars, newDocsVar(v))
+	}
+
+	if !hasGroups && len(groups) == 1 {
+		groups[0].Name = ""
+	}
+	return groups
+}
+
+func newDocsVar(v VarEntry) docsVar {
+	d := docsVar{
+		Name:          v.Name,
+		Type:          typeSummary(v),
+		Required:      v.Required,
+		AllowedValues: v.AllowedValues,
+		Description:   v.Description,
+	}
+	if v.DefaultValue != nil {
+		if v.Secret {
+			d.HiddenDefault = true
+		} else {
+			d.Default = defaultValueString(v)
+		}
+	}
+	if v.Pattern != nil {
+		d.Pattern = *v.Pattern
+	}
+	return d
+}
+
+func renderMarkdown(groups []docsGroup, title string) string {
+	var b strings.Builder
+	b.WriteString("# " + markdownText(title) + "\n")
+
+	for _, group := range groups {
+		if group.Name != "" {
+			b.WriteString("\n## " + markdownText(group.Name) + "\n")
+		}
+		b.WriteString("\n| Name | Type | Required | Default | Pattern | Allowed values | " +
+			"Description |\n|---|---|---|---|---|---|---|\n")
+
+		for _, v := range group.Vars {
+			var allowed []string
+			for _, value := range v.AllowedValues {
+				allowed = append(allowed, markdownCode(value))
+			}
+
+			required := "no"
+			if v.Required {
+				required = "yes"
+			}
+
+			defaultValue := markdownCode(v.Default)
+			if v.HiddenDefault {
+				defaultValue = "_" + hiddenSecretDefault + "_"
+			}
+
+			cells := []string{
+				markdownCode(v.Name),
+				markdownText(v.Type),
+				required,
+				defaultValue,
+				markdownCode(v.Pattern),
+				strings.Join(allowed, ", "),
+				markdownText(v.Description),
+			}
+			b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+	}
+
+	return b.String()
+}
+
+// markdownText escapes text for a Markdown table cell
+func markdownText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		"|", `\|`,
+		"<", "&lt;",
+		">", "&gt;",
+		"\r\n", "<br>",
+		"\n", "<br>",
+	).Replace(text)
+}
+
+// markdownCode wraps text in a code span which may be put in a table cell
+func markdownCode(text string) string {
+	if text == "" {
+		return ""
+	}
+
+	// A code span is delimited by a backtick string longer than any backtick run inside it
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	text = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ").Replace(text)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return fence + text + fence
+}
+
+var htmlDocsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+code { white-space: pre-wrap; }
+td.description { white-space: pre-line; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Groups}}{{if .Name}}<h2>{{.Name}}</h2>
+{{end}}<table>
+<thead>
+<tr>
+<th>Name</th><th>Type</th><th>Required</th><th>Default</th>
+<th>Pattern</th><th>Allowed values</th><th>Description</th>
+</tr>
+</thead>
+<tbody>
+{{range .Vars}}<tr>
+<td><code>{{.Name}}</code></td>
+<td>{{.Type}}</td>
+<td>{{if .Required}}yes{{else}}no{{end}}</td>
+<td>{{if .HiddenDefault}}<em>` + hiddenSecretDefault + `</em>
+{{- else if .Default}}<code>{{.Default}}</code>{{end}}</td>
+<td>{{if .Pattern}}<code>{{.Pattern}}</code>{{end}}</td>
+<td>{{range $i, $v := .AllowedValues}}{{if $i}}, {{end}}<code>{{$v}}</code>{{end}}</td>
+<td class="description">{{.Description}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+{{end}}</body>
+</html>
+`))
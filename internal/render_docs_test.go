@@ -0,0 +1,113 @@
+package internal_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestRenderDocs(t *testing.T) {
+	config := internal.Config{
+		Vars: []internal.VarEntry{
+			{
+				Name:         "PORT",
+				Required:     true,
+				Type:         internal.IntType,
+				DefaultValue: 8080,
+				Description:  "Port of the HTTP server",
+				Group:        "Server",
+			},
+			{
+				Name:          "MODE",
+				Type:          internal.StringType,
+				AllowedValues: []string{"dev", "prod"},
+				Description:   "Mode | <b>not bold</b>",
+			},
+			{
+				Name:         "DB_PASSWORD",
+				Type:         internal.StringType,
+				DefaultValue: "hunter2",
+				Secret:       true,
+				Pattern:      StringPtr("^[^|]+$"),
+				Group:        "Database",
+			},
+		},
+	}
+
+	t.Run("Markdown", func(t *testing.T) {
+		docs, err := internal.RenderDocs(&config, internal.MarkdownDocs, "Service env")
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		const expected = `# Service env
+
+## Server
+
+| Name | Type | Required | Default | Pattern | Allowed values | Description |
+|---|---|---|---|---|---|---|
+| ` + "`PORT`" + ` | int | yes | ` + "`8080`" + ` |  |  | Port of the HTTP server |
+
+## General
+
+| Name | Type | Required | Default | Pattern | Allowed values | Description |
+|---|---|---|---|---|---|---|
+| ` + "`MODE`" + ` | string | no |  |  | ` +
+			"`dev`, `prod`" + ` | Mode \| &lt;b&gt;not bold&lt;/b&gt; |
+
+## Database
+
+| Name | Type | Required | Default | Pattern | Allowed values | Description |
+|---|---|---|---|---|---|---|
+| ` + "`DB_PASSWORD`" + ` | string | no | _(secret)_ | ` + "`^[^\\|]+$`" + ` |  |  |
+`
+		if docs != expected {
+			t.Errorf("expected:\n%s\nActual:\n%s", expected, docs)
+		}
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		docs, err := internal.RenderDocs(&config, internal.HTMLDocs, "Service <env>")
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		expectedParts := []string{
+			"<title>Service &lt;env&gt;</title>",
+			"<h2>Server</h2>",
+			"<td><code>PORT</code></td>",
+			"<td><code>8080</code></td>",
+			"<code>dev</code>, <code>prod</code>",
+			"Mode | &lt;b&gt;not bold&lt;/b&gt;",
+			"<em>(secret)</em>",
+		}
+		for _, expected := range expectedParts {
+			if !strings.Contains(docs, expected) {
+				t.Errorf("expected html to contain \"%s\", got:\n%s", expected, docs)
+			}
+		}
+		if strings.Contains(docs, "hunter2") {
+			t.Error("default value of secret var must not be printed")
+		}
+	})
+
+	t.Run("No groups", func(t *testing.T) {
+		c := internal.Config{Vars: []internal.VarEntry{{Name: "PORT", Type: internal.IntType}}}
+
+		docs, err := internal.RenderDocs(&c, internal.MarkdownDocs, "Env")
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		if strings.Contains(docs, "##") {
+			t.Errorf("expected no sections, got:\n%s", docs)
+		}
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		_, err := internal.RenderDocs(&config, "pdf", "Env")
+		if err == nil || !strings.Contains(err.Error(), "unsupported docs format \"pdf\"") {
+			t.Fatalf("expected error about unsupported format, got %v", err)
+		}
+	})
+}
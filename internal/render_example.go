@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ExamplePlaceholder is the value of required vars which have to be filled in by hand
+const ExamplePlaceholder = "CHANGE_ME"
+
+const exampleHeader = `# Generated from the schema by "envcheck example", do not edit by hand.
+# Commented out vars are optional.
+`
+
+/*
+This function renders .env.example content for c. Every var gets its description and a summary
+of its rules as comments, followed by its line:
+  - a var with a default value shows the default;
+  - a required var without a default is left empty, or gets ExamplePlaceholder if it is secret;
+  - an optional var without a default is commented out, so copying the example does not set it.
+
+Default values of secret vars are never shown. The output depends only on c, so it can be
+compared with a committed file to detect drift.
+*/
+func RenderExample(c *Config) string {
+	var b strings.Builder
+	b.WriteString(exampleHeader)
+
+	for _, v := range c.Vars {
+		b.WriteString("\n")
+		for _, line := range strings.Split(v.Description, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				b.WriteString("# " + line + "\n")
+			}
+		}
+		b.WriteString("# " + exampleRules(v) + "\n")
+
+		switch {
+		case v.DefaultValue != nil && !v.Secret:
+			value := defaultValueString(v)
+			b.WriteString(v.Name + "=" + QuoteDotenvValue(value) + "\n")
+		case v.Required && v.Secret:
+			b.WriteString(v.Name + "=" + ExamplePlaceholder + "\n")
+		case v.Required && v.DefaultValue == nil:
+			b.WriteString(v.Name + "=\n")
+		default:
+			b.WriteString("# " + v.Name + "=\n")
+		}
+	}
+
+	return b.String()
+}
+
+// exampleRules describes rules of v in one line, like "string, required, secret"
+func exampleRules(v VarEntry) string {
+	rules := []string{typeSummary(v)}
+	if v.Required {
+		rules = append(rules, "required")
+	}
+	if v.Secret {
+		rules = append(rules, "secret")
+	}
+	if v.Pattern != nil {
+		rules = append(rules, "pattern "+*v.Pattern)
+	}
+	if len(v.AllowedValues) > 0 {
+		rules = append(rules, "one of "+strings.Join(v.AllowedValues, " | "))
+	}
+	return strings.Join(rules, ", ")
+}
+
+// Values which can be written to a .env file without quotes
+var plainDotenvValue = regexp.MustCompile(`^[a-zA-Z0-9_./:,@%+=-]*$`)
+
+/*
+QuoteDotenvValue returns value as it should be written in a .env file: as is if it is plain, in
+double quotes with escapes otherwise, so ParseDotenv reads the same value back and does not expand
+references in it.
+*/
+func QuoteDotenvValue(value string) string {
+	if plainDotenvValue.MatchString(value) {
+		return value
+	}
+
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`$`, `\$`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	)
+	return `"` + replacer.Replace(value) + `"`
+}
@@ -0,0 +1,85 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestRenderExample(t *testing.T) {
+	config := internal.Config{
+		Vars: []internal.VarEntry{
+			{
+				Name:         "PORT",
+				Required:     true,
+				Type:         internal.IntType,
+				DefaultValue: 8080,
+				Description:  "Port of the HTTP server",
+			},
+			{
+				Name:     "HOST",
+				Required: true,
+				Type:     internal.StringType,
+				Pattern:  StringPtr("^[a-z.]+$"),
+			},
+			{Name: "API_TOKEN", Required: true, Type: internal.StringType, Secret: true},
+			{Name: "DB_PASSWORD", Type: internal.StringType, Secret: true, DefaultValue: "hunter2"},
+			{
+				Name:         "GREETING",
+				Type:         internal.StringType,
+				DefaultValue: "Hi \"$USER\"\n# not a comment",
+			},
+			{Name: "DEBUG", Type: internal.BoolType},
+		},
+	}
+
+	const expected = `# Generated from the schema by "envcheck example", do not edit by hand.
+# Commented out vars are optional.
+
+# Port of the HTTP server
+# int, required
+PORT=8080
+
+# string, required, pattern ^[a-z.]+$
+HOST=
+
+# string, required, secret
+API_TOKEN=CHANGE_ME
+
+# string, secret
+# DB_PASSWORD=
+
+# string
+GREETING="Hi \"\$USER\"\n# not a comment"
+
+# bool
+# DEBUG=
+`
+
+	example := internal.RenderExample(&config)
+	if example != expected {
+		t.Fatalf("expected:\n%s\nActual:\n%s", expected, example)
+	}
+
+	t.Run("Values are read back", func(t *testing.T) {
+		entries, err := internal.ParseDotenv(".env.example", example)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		entries, err = internal.ExpandDotenv(entries, lookupInMap(nil), internal.ProcessEnvWins)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		values := make(map[string]string)
+		for _, entry := range entries {
+			values[entry.Key] = entry.Value
+		}
+		if greeting := config.Vars[4].DefaultValue; values["GREETING"] != greeting {
+			t.Errorf("expected GREETING %q, got %q", greeting, values["GREETING"])
+		}
+		if _, isSet := values["DEBUG"]; isSet {
+			t.Error("optional var without default must not be set")
+		}
+	})
+}
@@ -0,0 +1,326 @@
+package internal
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExportFormat is the output format of RenderExport
+type ExportFormat string
+
+const (
+	// export lines for POSIX shells
+	ShellExport ExportFormat = "sh"
+	// set -gx lines for fish
+	FishExport ExportFormat = "fish"
+	// $env: assignments for PowerShell
+	PowerShellExport ExportFormat = "powershell"
+	// file for docker run --env-file
+	DockerExport ExportFormat = "docker"
+	// file for EnvironmentFile= of systemd units
+	SystemdExport ExportFormat = "systemd"
+	// JSON object of var names and values
+	JSONExport ExportFormat = "json"
+	// YAML mapping of var names and values
+	YAMLExport ExportFormat = "yaml"
+	// lines to append to $GITHUB_ENV in GitHub Actions
+	GitHubExport ExportFormat = "github"
+	// ConfigMap and Secret manifests, secret vars go to the Secret
+	KubernetesExport ExportFormat = "kubernetes"
+)
+
+type ExportOptions struct {
+	// Name of Kubernetes manifests
+	Name string
+	// Namespace of Kubernetes manifests, omitted if empty
+	Namespace string
+}
+
+// Names which can be used as vars in shells and env files
+var exportVarName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Names which can be used as keys of ConfigMap and Secret data
+var kubernetesKey = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+type exportVar struct {
+	Name   string
+	Value  string
+	Secret bool
+}
+
+/*
+This function renders values of the vars declared in c which are set in env, in the order of the
+schema, so they can be passed to another system. Vars which are not declared are not exported.
+Values are quoted and escaped as the target format requires, so it reads back exactly the same
+values. An error is returned if a name or value can not be represented in the format, e.g. a
+multiline value in a Docker env file.
+
+Secret vars are exported like the others, except in KubernetesExport where they go to a Secret
+instead of the ConfigMap.
+*/
+func RenderExport(
+	c *Config,
+	env map[string]string,
+	format ExportFormat,
+	o ExportOptions,
+) (string, error) {
+	var vars []exportVar
+	for _, v := range c.Vars {
+		if value, isSet := env[v.Name]; isSet {
+			vars = append(vars, exportVar{Name: v.Name, Value: value, Secret: v.Secret})
+		}
+	}
+
+	switch format {
+	case ShellExport:
+		return renderExportLines(vars, func(v exportVar) (string, error) {
+			return "export " + v.Name + "=" + shellQuote(v.Value), nil
+		})
+	case FishExport:
+		return renderExportLines(vars, func(v exportVar) (string, error) {
+			return "set -gx " + v.Name + " " + fishQuote(v.Value), nil
+		})
+	case PowerShellExport:
+		return renderExportLines(vars, func(v exportVar) (string, error) {
+			return "$env:" + v.Name + " = " + powerShellQuote(v.Value), nil
+		})
+	case DockerExport:
+		return renderExportLines(vars, dockerLine)
+	case SystemdExport:
+		return renderExportLines(vars, func(v exportVar) (string, error) {
+			return v.Name + "=" + systemdQuote(v.Value), nil
+		})
+	case GitHubExport:
+		return renderExportLines(vars, gitHubLine)
+	case JSONExport:
+		return renderJSONExport(vars)
+	case YAMLExport:
+		return encodeYAML(stringMapping(vars))
+	case KubernetesExport:
+		return renderKubernetesExport(vars, o)
+	default:
+		return "", fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// renderExportLines renders one line, or block of lines, per var for formats read by shells
+func renderExportLines(
+	vars []exportVar,
+	line func(v exportVar) (string, error),
+) (string, error) {
+	var b strings.Builder
+	for _, v := range vars {
+		if !exportVarName.MatchString(v.Name) {
+			return "", fmt.Errorf(
+				"var %s can not be exported, its name is not a valid identifier",
+				v.Name,
+			)
+		}
+
+		l, err := line(v)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(l + "\n")
+	}
+	return b.String(), nil
+}
+
+// shellQuote puts value in single quotes, in which POSIX shells do not interpret anything
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// fishQuote puts value in single quotes, in which fish only interprets \\ and \'
+func fishQuote(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+}
+
+/*
+powerShellQuote puts value in single quotes, in which PowerShell only interprets doubled quotes.
+PowerShell also takes typographic single quotes for quotes, so they are doubled as well.
+*/
+func powerShellQuote(value string) string {
+	return "'" + strings.NewReplacer(
+		"'", "''",
+		"‘", "‘‘",
+		"’", "’’",
+		"‚", "‚‚",
+		"‛", "‛‛",
+	).Replace(value) + "'"
+}
+
+// dockerLine renders a line of docker env file, which takes values as is up to the end of line
+func dockerLine(v exportVar) (string, error) {
+	if strings.ContainsAny(v.Value, "\r\n") {
+		return "", fmt.Errorf(
+			"var %s can not be exported to docker env file, its value has a line break",
+			v.Name,
+		)
+	}
+	return v.Name + "=" + v.Value, nil
+}
+
+/*
+systemdQuote puts value in double quotes. systemd interprets backslash escapes of \, ", ` and $
+in them and keeps line breaks, but does not expand references.
+*/
+func systemdQuote(value string) string {
+	return `"` + strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"`", "\\`",
+		"$", `\$`,
+	).Replace(value) + `"`
+}
+
+/*
+gitHubLine renders a line of $GITHUB_ENV, which takes single-line values as is. Multiline values
+are written as heredoc with a delimiter which the value does not contain.
+*/
+func gitHubLine(v exportVar) (string, error) {
+	if !strings.ContainsAny(v.Value, "\r\n") {
+		return v.Name + "=" + v.Value, nil
+	}
+
+	delimiter := "ENVCHECK_EOF"
+	for i := 1; strings.Contains(v.Value, delimiter); i++ {
+		delimiter = fmt.Sprintf("ENVCHECK_EOF_%d", i)
+	}
+	return v.Name + "<<" + delimiter + "\n" + v.Value + "\n" + delimiter, nil
+}
+
+// renderJSONExport renders vars as a JSON object, keeping the order of the schema
+func renderJSONExport(vars []exportVar) (string, error) {
+	if len(vars) == 0 {
+		return "{}\n", nil
+	}
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for i, v := range vars {
+		name, err := json.Marshal(v.Name)
+		if err != nil {
+			return "", err
+		}
+		value, err := json.Marshal(v.Value)
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString("  " + string(name) + ": " + string(value))
+		if i < len(vars)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+/*
+renderKubernetesExport renders a ConfigMap with values of vars which are not secret and, if there
+are secret vars, a Secret with their values encoded in base64.
+*/
+func renderKubernetesExport(vars []exportVar, o ExportOptions) (string, error) {
+	if o.Name == "" {
+		return "", errors.New("name of kubernetes manifests is not set")
+	}
+
+	var public, secret []exportVar
+	for _, v := range vars {
+		if !kubernetesKey.MatchString(v.Name) {
+			return "", fmt.Errorf(
+				"var %s can not be exported, its name is not a valid kubernetes key",
+				v.Name,
+			)
+		}
+		if v.Secret {
+			secret = append(secret, exportVar{
+				Name:  v.Name,
+				Value: base64.StdEncoding.EncodeToString([]byte(v.Value)),
+			})
+		} else {
+			public = append(public, v)
+		}
+	}
+
+	configMap, err := encodeYAML(kubernetesManifest("ConfigMap", o, public))
+	if err != nil {
+		return "", err
+	}
+	if len(secret) == 0 {
+		return configMap, nil
+	}
+
+	manifest := kubernetesManifest("Secret", o, secret)
+	manifest.Content = append(manifest.Content, scalarNode("type"), scalarNode("Opaque"))
+	secretManifest, err := encodeYAML(manifest)
+	if err != nil {
+		return "", err
+	}
+	return configMap + "---\n" + secretManifest, nil
+}
+
+// kubernetesManifest returns a manifest of kind with vars as data
+func kubernetesManifest(kind string, o ExportOptions, vars []exportVar) *yaml.Node {
+	metadata := mappingNode("name", o.Name)
+	if o.Namespace != "" {
+		metadata.Content = append(metadata.Content,
+			scalarNode("namespace"), scalarNode(o.Namespace),
+		)
+	}
+
+	manifest := mappingNode("apiVersion", "v1", "kind", kind)
+	manifest.Content = append(manifest.Content,
+		scalarNode("metadata"), metadata,
+		scalarNode("data"), stringMapping(vars),
+	)
+	return manifest
+}
+
+// stringMapping returns a YAML mapping of vars, in which every value is a string
+func stringMapping(vars []exportVar) *yaml.Node {
+	node := mappingNode()
+	for _, v := range vars {
+		node.Content = append(node.Content, scalarNode(v.Name), scalarNode(v.Value))
+	}
+	if len(vars) == 0 {
+		// Written as {}, an empty block mapping would be read as null
+		node.Style = yaml.FlowStyle
+	}
+	return node
+}
+
+// mappingNode returns a YAML mapping of keys and values given in turn
+func mappingNode(pairs ...string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, s := range pairs {
+		node.Content = append(node.Content, scalarNode(s))
+	}
+	return node
+}
+
+// scalarNode returns a YAML string, which the encoder quotes if it would be read as another type
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func encodeYAML(node *yaml.Node) (string, error) {
+	var b strings.Builder
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return "", fmt.Errorf("failed to encode yaml\n\t%w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode yaml\n\t%w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,220 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenderExport(t *testing.T) {
+	config := internal.Config{
+		Vars: []internal.VarEntry{
+			{Name: "PORT", Type: internal.IntType},
+			{Name: "GREETING", Type: internal.StringType},
+			{Name: "API_TOKEN", Type: internal.StringType, Secret: true},
+			{Name: "UNSET", Type: internal.StringType},
+		},
+	}
+	env := map[string]string{
+		"PORT":      "8080",
+		"GREETING":  `it's "$HOME" \n`,
+		"API_TOKEN": "s3cr3t",
+		"HOME":      "/root",
+	}
+
+	testCases := []struct {
+		name     string
+		format   internal.ExportFormat
+		expected string
+	}{
+		{
+			name:   "POSIX shell",
+			format: internal.ShellExport,
+			expected: `export PORT='8080'
+export GREETING='it'\''s "$HOME" \n'
+export API_TOKEN='s3cr3t'
+`,
+		},
+		{
+			name:   "fish",
+			format: internal.FishExport,
+			expected: `set -gx PORT '8080'
+set -gx GREETING 'it\'s "$HOME" \\n'
+set -gx API_TOKEN 's3cr3t'
+`,
+		},
+		{
+			name:   "PowerShell",
+			format: internal.PowerShellExport,
+			expected: `$env:PORT = '8080'
+$env:GREETING = 'it''s "$HOME" \n'
+$env:API_TOKEN = 's3cr3t'
+`,
+		},
+		{
+			name:   "Docker",
+			format: internal.DockerExport,
+			expected: `PORT=8080
+GREETING=it's "$HOME" \n
+API_TOKEN=s3cr3t
+`,
+		},
+		{
+			name:   "systemd",
+			format: internal.SystemdExport,
+			expected: `PORT="8080"
+GREETING="it's \"\$HOME\" \\n"
+API_TOKEN="s3cr3t"
+`,
+		},
+		{
+			name:   "GitHub Actions",
+			format: internal.GitHubExport,
+			expected: `PORT=8080
+GREETING=it's "$HOME" \n
+API_TOKEN=s3cr3t
+`,
+		},
+		{
+			name:   "JSON",
+			format: internal.JSONExport,
+			expected: `{
+  "PORT": "8080",
+  "GREETING": "it's \"$HOME\" \\n",
+  "API_TOKEN": "s3cr3t"
+}
+`,
+		},
+		{
+			name:   "YAML",
+			format: internal.YAMLExport,
+			expected: `PORT: "8080"
+GREETING: it's "$HOME" \n
+API_TOKEN: s3cr3t
+`,
+		},
+		{
+			name:   "Kubernetes",
+			format: internal.KubernetesExport,
+			expected: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  PORT: "8080"
+  GREETING: it's "$HOME" \n
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app
+data:
+  API_TOKEN: czNjcjN0
+type: Opaque
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := internal.RenderExport(
+				&config,
+				env,
+				tc.format,
+				internal.ExportOptions{Name: "app"},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error \"%v\"", err)
+			}
+			if output != tc.expected {
+				t.Errorf("expected output\n%s\ngot\n%s", tc.expected, output)
+			}
+		})
+	}
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		_, err := internal.RenderExport(&config, env, "toml", internal.ExportOptions{})
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestRenderExportMultiline(t *testing.T) {
+	config := internal.Config{
+		Vars: []internal.VarEntry{{Name: "CERT", Type: internal.StringType, Secret: true}},
+	}
+	value := "-----BEGIN-----\nENVCHECK_EOF\n'quoted' \"$x\" `cmd`\n-----END-----\n"
+	env := map[string]string{"CERT": value}
+
+	render := func(t *testing.T, format internal.ExportFormat) string {
+		t.Helper()
+		output, err := internal.RenderExport(&config, env, format, internal.ExportOptions{
+			Name:      "app",
+			Namespace: "prod",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		return output
+	}
+
+	t.Run("POSIX shell reads the same value", func(t *testing.T) {
+		sh, err := exec.LookPath("sh")
+		if err != nil {
+			t.Skip("sh is not available")
+		}
+		script := render(t, internal.ShellExport) + `printf '%s' "$CERT"`
+		output, err := exec.Command(sh, "-c", script).Output()
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		if string(output) != value {
+			t.Errorf("expected value \"%s\", got \"%s\"", value, output)
+		}
+	})
+
+	t.Run("GitHub Actions delimiter is not in the value", func(t *testing.T) {
+		expected := "CERT<<ENVCHECK_EOF_1\n" + value + "\nENVCHECK_EOF_1\n"
+		if output := render(t, internal.GitHubExport); output != expected {
+			t.Errorf("expected output\n%s\ngot\n%s", expected, output)
+		}
+	})
+
+	t.Run("JSON and YAML read the same value", func(t *testing.T) {
+		var fromJSON, fromYAML map[string]string
+		if err := json.Unmarshal([]byte(render(t, internal.JSONExport)), &fromJSON); err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		if err := yaml.Unmarshal([]byte(render(t, internal.YAMLExport)), &fromYAML); err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		if fromJSON["CERT"] != value || fromYAML["CERT"] != value {
+			t.Errorf("expected value \"%s\", got \"%s\" and \"%s\"",
+				value, fromJSON["CERT"], fromYAML["CERT"])
+		}
+	})
+
+	t.Run("Kubernetes secret vars go to Secret", func(t *testing.T) {
+		output := render(t, internal.KubernetesExport)
+		const configMap = "kind: ConfigMap\nmetadata:\n  name: app\n  namespace: prod\ndata: {}\n"
+		if !strings.Contains(output, configMap) {
+			t.Errorf("expected empty ConfigMap, got\n%s", output)
+		}
+		if !strings.Contains(output, "kind: Secret") || strings.Contains(output, "BEGIN") {
+			t.Errorf("expected value encoded in Secret, got\n%s", output)
+		}
+	})
+
+	t.Run("Docker env file can not hold line breaks", func(t *testing.T) {
+		o := internal.ExportOptions{}
+		_, err := internal.RenderExport(&config, env, internal.DockerExport, o)
+		if err == nil || !strings.Contains(err.Error(), "line break") {
+			t.Errorf("expected line break error, got \"%v\"", err)
+		}
+	})
+}
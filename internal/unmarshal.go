@@ -0,0 +1,420 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+This function fills struct pointed by v with values of vars returned by lookupEnv. Fields are bound
+to vars declared in c by `env` tags, see envcheck.Unmarshal for details. All mismatches between
+field types and var types are reported, not only the first one.
+*/
+func Unmarshal(c *Config, lookupEnv LookupEnvFunc, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("expected non-nil pointer to struct, got %T", v)
+	}
+
+	d := &decoder{
+		vars:      make(map[string]VarEntry),
+		lookupEnv: lookupEnv,
+		nesting:   make(map[reflect.Type]bool),
+	}
+	for _, entry := range c.Vars {
+		d.vars[entry.Name] = entry
+	}
+
+	_, err := d.decodeStruct(rv.Elem(), "", "")
+	return err
+}
+
+type decoder struct {
+	vars      map[string]VarEntry
+	lookupEnv LookupEnvFunc
+	// Struct types which are being decoded, to find types nested in themselves
+	nesting map[reflect.Type]bool
+}
+
+/*
+decodeStruct fills fields of struct sv, path is used in error messages. Only embedded structs and
+fields with envPrefix tag are decoded as nested structs. It reports whether any var bound to sv
+is set.
+*/
+func (d *decoder) decodeStruct(sv reflect.Value, prefix, path string) (bool, error) {
+	var decodeError error = nil
+	anySet := false
+
+	st := sv.Type()
+	d.nesting[st] = true
+	defer delete(d.nesting, st)
+
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+
+		fv := sv.Field(i)
+		fieldPath := path + field.Name
+
+		name, ok := field.Tag.Lookup("env")
+		if !ok {
+			fieldPrefix, hasPrefix := field.Tag.Lookup("envPrefix")
+			if !hasPrefix && !(field.Anonymous && isStructField(field.Type)) {
+				continue
+			}
+			isSet, err := d.decodeNested(fv, prefix+fieldPrefix, fieldPath)
+			anySet = anySet || isSet
+			decodeError = errors.Join(decodeError, err)
+			continue
+		}
+
+		isSet, err := d.decodeField(fv, prefix+name)
+		if err != nil {
+			decodeError = errors.Join(decodeError, fmt.Errorf("field %s: %w", fieldPath, err))
+		}
+		anySet = anySet || isSet
+	}
+
+	return anySet, decodeError
+}
+
+/*
+decodeNested decodes struct which fv holds or points to. A nil pointer is set only if a var bound
+to the struct is set, so pointers to structs without values stay nil.
+*/
+func (d *decoder) decodeNested(fv reflect.Value, prefix, path string) (bool, error) {
+	if !isStructField(fv.Type()) {
+		err := fmt.Errorf("envPrefix is set, but %s is not a struct", fv.Type())
+		return false, fmt.Errorf("field %s: %w", path, err)
+	}
+	t := fv.Type()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if d.nesting[t] {
+		return false, fmt.Errorf("field %s: type %s is nested in itself", path, t)
+	}
+
+	if fv.Kind() == reflect.Struct {
+		return d.decodeStruct(fv, prefix, path+".")
+	}
+
+	target := fv
+	if fv.IsNil() {
+		target = reflect.New(t)
+	}
+	isSet, err := d.decodeStruct(target.Elem(), prefix, path+".")
+	if isSet && fv.IsNil() {
+		fv.Set(target)
+	}
+	return isSet, err
+}
+
+// isStructField reports whether field of type t holds or points to a struct
+func isStructField(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// decodeField fills field fv with value of var name and reports whether the var is set
+func (d *decoder) decodeField(fv reflect.Value, name string) (bool, error) {
+	entry, ok := d.vars[name]
+	if !ok {
+		return false, fmt.Errorf("var %s is not declared in schema", name)
+	}
+
+	t := fv.Type()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if !fieldMatchesEntry(t, entry) {
+		return false, fmt.Errorf("type %s does not match var %s of type %s", t, name, entry.Type)
+	}
+
+	value, isSet := d.lookupEnv(name)
+	if !isSet {
+		return false, nil
+	}
+
+	if err := setField(fv, entry, value); err != nil {
+		return true, fmt.Errorf("var %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// setField stores value of var entry in field fv, which must match the type of the var
+func setField(fv reflect.Value, entry VarEntry, value string) error {
+	t := fv.Type()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == parsedFieldTypes[entry.Type] {
+		return setParsedValue(fv, entry, value)
+	}
+
+	target := fv
+	if fv.Kind() == reflect.Pointer {
+		target = reflect.New(t).Elem()
+	}
+
+	var err error
+	switch {
+	case t.Kind() == reflect.String:
+		err = setValue(target, value)
+	case entry.Type == ListType || entry.Type == MapType:
+		err = setCollection(target, entry, value)
+	case entry.Type == SizeType || entry.Type == QuantityType:
+		err = setParsedNumber(target, entry, value)
+	case entry.Type == JSONType:
+		err = setJSON(target, entry, value)
+	default:
+		err = setValue(target, value)
+	}
+	if err != nil {
+		return err
+	}
+
+	if fv.Kind() == reflect.Pointer {
+		fv.Set(target.Addr())
+	}
+	return nil
+}
+
+/*
+setCollection validates value of list or map var entry and stores its items in slice or map v,
+each of them converted by setField according to the element of the var.
+*/
+func setCollection(v reflect.Value, entry VarEntry, value string) error {
+	if _, err := ParseValue(entry, value); err != nil {
+		return err
+	}
+
+	element := elementEntry(entry)
+	items := splitItems(entry, value)
+	if entry.Type == ListType {
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setField(slice.Index(i), element, item); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+
+	keys, items, _ := splitEntries(entry, items)
+	m := reflect.MakeMapWithSize(v.Type(), len(keys))
+	for i, key := range keys {
+		item := reflect.New(v.Type().Elem()).Elem()
+		if err := setField(item, element, items[i]); err != nil {
+			return fmt.Errorf("key %s: %w", key, err)
+		}
+		m.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), item)
+	}
+	v.Set(m)
+	return nil
+}
+
+// Types of fields which are filled with values returned by ParseValue, besides string fields
+var parsedFieldTypes = map[SupportedVarType]reflect.Type{
+	URLType:      reflect.TypeFor[url.URL](),
+	HostPortType: reflect.TypeFor[HostPort](),
+	IPType:       reflect.TypeFor[netip.Addr](),
+	CIDRType:     reflect.TypeFor[netip.Prefix](),
+	DurationType: reflect.TypeFor[time.Duration](),
+	TimeType:     reflect.TypeFor[time.Time](),
+	TimezoneType: reflect.TypeFor[time.Location](),
+	CronType:     reflect.TypeFor[CronSchedule](),
+}
+
+/*
+setParsedValue parses value of var entry with ParseValue and stores it in field fv, dereferencing
+the parsed value or allocating a pointer to it as the field requires.
+*/
+func setParsedValue(fv reflect.Value, entry VarEntry, value string) error {
+	parsed, err := ParseValue(entry, value)
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(parsed)
+	switch {
+	case rv.Type().AssignableTo(fv.Type()):
+		fv.Set(rv)
+	case rv.Kind() == reflect.Pointer:
+		fv.Set(rv.Elem())
+	default:
+		target := reflect.New(rv.Type())
+		target.Elem().Set(rv)
+		fv.Set(target)
+	}
+	return nil
+}
+
+// setParsedNumber parses value of size or quantity var entry and stores it in numeric field v
+func setParsedNumber(v reflect.Value, entry VarEntry, value string) error {
+	parsed, err := ParseValue(entry, value)
+	if err != nil {
+		return err
+	}
+
+	switch n := parsed.(type) {
+	case int64:
+		switch kind := v.Kind(); {
+		case isIntKind(kind) && !v.OverflowInt(n):
+			v.SetInt(n)
+		case isUintKind(kind) && !v.OverflowUint(uint64(n)):
+			v.SetUint(uint64(n))
+		default:
+			return fmt.Errorf("%q does not fit %s", value, v.Type())
+		}
+	case float64:
+		if v.OverflowFloat(n) {
+			return fmt.Errorf("%q does not fit %s", value, v.Type())
+		}
+		v.SetFloat(n)
+	}
+	return nil
+}
+
+/*
+setJSON validates value of json var entry and decodes it to v with encoding/json, so v may be a
+struct, a map, a slice or any other type which the JSON fits.
+*/
+func setJSON(v reflect.Value, entry VarEntry, value string) error {
+	if _, err := ParseValue(entry, value); err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(value), v.Addr().Interface()); err != nil {
+		return fmt.Errorf("JSON does not fit %s: %w", v.Type(), err)
+	}
+	return nil
+}
+
+/*
+fieldMatchesEntry reports whether field type t can hold var entry. List vars match slices and map
+vars match maps with string keys, whose elements match the element of the var. Json vars match
+any type, their values are decoded to it.
+*/
+func fieldMatchesEntry(t reflect.Type, entry VarEntry) bool {
+	isCollection := entry.Type == ListType || entry.Type == MapType
+	switch {
+	case entry.Type == ListType && t.Kind() == reflect.Slice,
+		entry.Type == MapType && t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		element := t.Elem()
+		if element.Kind() == reflect.Pointer {
+			element = element.Elem()
+		}
+		return element.Kind() != reflect.Slice && fieldMatchesEntry(element, elementEntry(entry))
+	case isCollection:
+		return t.Kind() == reflect.String
+	case entry.Type == JSONType:
+		return true
+	default:
+		return fieldMatchesType(t, entry.Type)
+	}
+}
+
+func fieldMatchesType(t reflect.Type, varType SupportedVarType) bool {
+	if t.Kind() == reflect.Slice {
+		return (varType == StringType || varType == AnyType) && isScalarKind(t.Elem().Kind())
+	}
+
+	switch varType {
+	case StringType:
+		return t.Kind() == reflect.String
+	case IntType, PortType:
+		return isIntKind(t.Kind()) || isUintKind(t.Kind())
+	case FloatType:
+		return t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+	case BoolType:
+		return t.Kind() == reflect.Bool
+	case AnyType:
+		return isScalarKind(t.Kind())
+	case URLType, HostPortType, IPType, CIDRType, DurationType, TimeType, TimezoneType, CronType:
+		return t.Kind() == reflect.String || t == parsedFieldTypes[varType]
+	case SizeType:
+		return t.Kind() == reflect.String || isIntKind(t.Kind()) || isUintKind(t.Kind())
+	case QuantityType:
+		kind := t.Kind()
+		return kind == reflect.String || kind == reflect.Float32 || kind == reflect.Float64
+	default:
+		return false
+	}
+}
+
+// setValue parses value according to the kind of v and stores it in v
+func setValue(v reflect.Value, value string) error {
+	if v.Kind() == reflect.Slice {
+		var parts []string
+		if value != "" {
+			parts = strings.Split(value, ",")
+		}
+
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setValue(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+
+	switch kind := v.Kind(); {
+	case kind == reflect.String:
+		v.SetString(value)
+	case kind == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a valid bool", value)
+		}
+		v.SetBool(b)
+	case isIntKind(kind):
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid %s", value, v.Type())
+		}
+		v.SetInt(i)
+	case isUintKind(kind):
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid %s", value, v.Type())
+		}
+		v.SetUint(u)
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a valid %s", value, v.Type())
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uint64
+}
+
+func isScalarKind(k reflect.Kind) bool {
+	return k == reflect.String || k == reflect.Bool || isIntKind(k) || isUintKind(k) ||
+		k == reflect.Float32 || k == reflect.Float64
+}
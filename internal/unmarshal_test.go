@@ -0,0 +1,445 @@
+package internal_test
+
+import (
+	"net/netip"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestUnmarshal(t *testing.T) {
+	config := internal.Config{
+		Vars: []internal.VarEntry{
+			{Name: "NAME", Type: internal.StringType},
+			{Name: "PORT", Type: internal.IntType},
+			{Name: "WORKERS", Type: internal.IntType},
+			{Name: "RATIO", Type: internal.FloatType},
+			{Name: "DEBUG", Type: internal.BoolType},
+			{Name: "TIMEOUT", Type: internal.IntType},
+			{Name: "HOSTS", Type: internal.StringType},
+			{Name: "EXTRA", Type: internal.AnyType},
+			{Name: "DB_HOST", Type: internal.StringType},
+			{Name: "DB_PORT", Type: internal.IntType},
+			{Name: "CACHE_SIZE", Type: internal.IntType},
+		},
+	}
+
+	env := map[string]string{
+		"NAME":       "service",
+		"PORT":       "8080",
+		"WORKERS":    "4",
+		"RATIO":      "0.5",
+		"DEBUG":      "true",
+		"HOSTS":      "a.com, b.com",
+		"EXTRA":      "42",
+		"DB_HOST":    "db",
+		"DB_PORT":    "5432",
+		"CACHE_SIZE": "128",
+	}
+
+	type database struct {
+		Host string `env:"HOST"`
+		Port uint16 `env:"PORT"`
+	}
+
+	type cache struct {
+		Size int `env:"SIZE"`
+	}
+
+	type settings struct {
+		Name    string   `env:"NAME"`
+		Port    int      `env:"PORT"`
+		Workers uint     `env:"WORKERS"`
+		Ratio   float32  `env:"RATIO"`
+		Debug   *bool    `env:"DEBUG"`
+		Timeout *int     `env:"TIMEOUT"`
+		Hosts   []string `env:"HOSTS"`
+		Extra   int64    `env:"EXTRA"`
+		DB      database `envPrefix:"DB_"`
+		Cache   *cache   `envPrefix:"CACHE_"`
+		Ignored string
+		private string
+	}
+
+	t.Run("Valid struct", func(t *testing.T) {
+		debug := true
+		expected := settings{
+			Name:    "service",
+			Port:    8080,
+			Workers: 4,
+			Ratio:   0.5,
+			Debug:   &debug,
+			Hosts:   []string{"a.com", "b.com"},
+			Extra:   42,
+			DB:      database{Host: "db", Port: 5432},
+			Cache:   &cache{Size: 128},
+			Ignored: "kept",
+		}
+
+		s := settings{Ignored: "kept"}
+		err := internal.Unmarshal(&config, lookupInMap(env), &s)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		if !reflect.DeepEqual(s, expected) {
+			t.Errorf("expected: %+v\n Actual: %+v", expected, s)
+		}
+	})
+
+	t.Run("Network fields", func(t *testing.T) {
+		networkConfig := internal.Config{
+			Vars: []internal.VarEntry{
+				{Name: "API_URL", Type: internal.URLType},
+				{Name: "API_URL_STRING", Type: internal.URLType},
+				{Name: "ADDR", Type: internal.HostPortType},
+				{Name: "BIND_IP", Type: internal.IPType},
+				{Name: "SUBNET", Type: internal.CIDRType},
+				{Name: "METRICS_PORT", Type: internal.PortType},
+			},
+		}
+		networkEnv := map[string]string{
+			"API_URL":        "https://api.example.com/v1",
+			"API_URL_STRING": "https://api.example.com/v1",
+			"ADDR":           "[::1]:8080",
+			"BIND_IP":        "10.0.0.1",
+			"SUBNET":         "10.0.0.0/8",
+			"METRICS_PORT":   "9090",
+		}
+
+		var s struct {
+			APIURL       *url.URL          `env:"API_URL"`
+			APIURLString string            `env:"API_URL_STRING"`
+			Addr         internal.HostPort `env:"ADDR"`
+			BindIP       netip.Addr        `env:"BIND_IP"`
+			Subnet       netip.Prefix      `env:"SUBNET"`
+			MetricsPort  uint16            `env:"METRICS_PORT"`
+		}
+		err := internal.Unmarshal(&networkConfig, lookupInMap(networkEnv), &s)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		if s.APIURL == nil || s.APIURL.Host != "api.example.com" || s.APIURL.Path != "/v1" {
+			t.Errorf("unexpected url %v", s.APIURL)
+		}
+		if s.APIURLString != networkEnv["API_URL_STRING"] {
+			t.Errorf("unexpected url string %s", s.APIURLString)
+		}
+		if s.Addr != (internal.HostPort{Host: "::1", Port: 8080}) {
+			t.Errorf("unexpected host and port %+v", s.Addr)
+		}
+		if s.BindIP != netip.MustParseAddr("10.0.0.1") {
+			t.Errorf("unexpected ip %v", s.BindIP)
+		}
+		if s.Subnet != netip.MustParsePrefix("10.0.0.0/8") {
+			t.Errorf("unexpected subnet %v", s.Subnet)
+		}
+		if s.MetricsPort != 9090 {
+			t.Errorf("unexpected port %d", s.MetricsPort)
+		}
+	})
+
+	t.Run("Time fields", func(t *testing.T) {
+		timeConfig := internal.Config{
+			Vars: []internal.VarEntry{
+				{Name: "TIMEOUT", Type: internal.DurationType},
+				{Name: "RETRY", Type: internal.DurationType},
+				{Name: "START", Type: internal.TimeType, Layout: "2006-01-02"},
+				{Name: "TZ", Type: internal.TimezoneType},
+				{Name: "SCHEDULE", Type: internal.CronType},
+			},
+		}
+		timeEnv := map[string]string{
+			"TIMEOUT":  "5s",
+			"RETRY":    "100ms",
+			"START":    "2024-01-02",
+			"TZ":       "UTC",
+			"SCHEDULE": "0 * * * *",
+		}
+
+		var s struct {
+			Timeout  time.Duration         `env:"TIMEOUT"`
+			Retry    *time.Duration        `env:"RETRY"`
+			Start    time.Time             `env:"START"`
+			TZ       *time.Location        `env:"TZ"`
+			Schedule internal.CronSchedule `env:"SCHEDULE"`
+		}
+		err := internal.Unmarshal(&timeConfig, lookupInMap(timeEnv), &s)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		if s.Timeout != 5*time.Second || s.Retry == nil || *s.Retry != 100*time.Millisecond {
+			t.Errorf("unexpected durations %v and %v", s.Timeout, s.Retry)
+		}
+		if s.Start != time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC) {
+			t.Errorf("unexpected time %v", s.Start)
+		}
+		if s.TZ != time.UTC {
+			t.Errorf("unexpected location %v", s.TZ)
+		}
+		if s.Schedule.String() != "0 * * * *" {
+			t.Errorf("unexpected schedule %v", s.Schedule)
+		}
+	})
+
+	t.Run("Size and quantity fields", func(t *testing.T) {
+		quantityConfig := internal.Config{
+			Vars: []internal.VarEntry{
+				{Name: "MEMORY", Type: internal.SizeType},
+				{Name: "UPLOAD", Type: internal.SizeType},
+				{Name: "CPU", Type: internal.QuantityType, Unit: "%"},
+			},
+		}
+		quantityEnv := map[string]string{"MEMORY": "512MiB", "UPLOAD": "10MB", "CPU": "50%"}
+
+		var s struct {
+			Memory uint64  `env:"MEMORY"`
+			Upload *int32  `env:"UPLOAD"`
+			CPU    float64 `env:"CPU"`
+		}
+		err := internal.Unmarshal(&quantityConfig, lookupInMap(quantityEnv), &s)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		if s.Memory != 512<<20 || s.Upload == nil || *s.Upload != 10_000_000 || s.CPU != 50 {
+			t.Errorf("unexpected values %d, %v and %v", s.Memory, s.Upload, s.CPU)
+		}
+
+		var small struct {
+			Memory int16 `env:"MEMORY"`
+		}
+		err = internal.Unmarshal(&quantityConfig, lookupInMap(quantityEnv), &small)
+		if err == nil || !strings.Contains(err.Error(), "\"512MiB\" does not fit int16") {
+			t.Errorf("expected overflow error, got \"%v\"", err)
+		}
+	})
+
+	t.Run("List and map fields", func(t *testing.T) {
+		collectionConfig := internal.Config{
+			Vars: []internal.VarEntry{
+				{
+					Name:    "PORTS",
+					Type:    internal.ListType,
+					Element: &internal.VarEntry{Type: internal.PortType},
+				},
+				{
+					Name:    "TIMEOUTS",
+					Type:    internal.MapType,
+					Element: &internal.VarEntry{Type: internal.DurationType},
+				},
+				{
+					Name:    "ORIGINS",
+					Type:    internal.ListType,
+					Element: &internal.VarEntry{Type: internal.URLType},
+				},
+			},
+		}
+		collectionEnv := map[string]string{
+			"PORTS":    "80, 443",
+			"TIMEOUTS": "read=5s,write=1m",
+			"ORIGINS":  "https://a.example,https://b.example",
+		}
+
+		var s struct {
+			Ports    []uint16                 `env:"PORTS"`
+			Timeouts map[string]time.Duration `env:"TIMEOUTS"`
+			Origins  []*url.URL               `env:"ORIGINS"`
+		}
+		err := internal.Unmarshal(&collectionConfig, lookupInMap(collectionEnv), &s)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		if !reflect.DeepEqual(s.Ports, []uint16{80, 443}) {
+			t.Errorf("unexpected ports %v", s.Ports)
+		}
+		expectedTimeouts := map[string]time.Duration{"read": 5 * time.Second, "write": time.Minute}
+		if !reflect.DeepEqual(s.Timeouts, expectedTimeouts) {
+			t.Errorf("unexpected timeouts %v", s.Timeouts)
+		}
+		if len(s.Origins) != 2 || s.Origins[1].Host != "b.example" {
+			t.Errorf("unexpected origins %v", s.Origins)
+		}
+
+		var mismatched struct {
+			Timeouts map[string]int `env:"TIMEOUTS"`
+		}
+		err = internal.Unmarshal(&collectionConfig, lookupInMap(collectionEnv), &mismatched)
+		expected := "type map[string]int does not match var TIMEOUTS of type map"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected \"%s\", got \"%v\"", expected, err)
+		}
+
+		var small struct {
+			Ports []int8 `env:"PORTS"`
+		}
+		err = internal.Unmarshal(&collectionConfig, lookupInMap(collectionEnv), &small)
+		if err == nil || !strings.Contains(err.Error(), "item 1: \"443\" is not a valid int8") {
+			t.Errorf("expected overflow error, got \"%v\"", err)
+		}
+	})
+
+	t.Run("Json fields", func(t *testing.T) {
+		jsonConfig := internal.Config{
+			Vars: []internal.VarEntry{
+				{Name: "FEATURE_CONFIG", Type: internal.JSONType},
+				{Name: "LIMITS", Type: internal.JSONType},
+			},
+		}
+		jsonEnv := map[string]string{
+			"FEATURE_CONFIG": `{"mode": "safe", "flags": ["beta"]}`,
+			"LIMITS":         `{"cpu": 2}`,
+		}
+
+		var s struct {
+			Feature *struct {
+				Mode  string   `json:"mode"`
+				Flags []string `json:"flags"`
+			} `env:"FEATURE_CONFIG"`
+			Limits map[string]int `env:"LIMITS"`
+			Raw    string         `env:"LIMITS"`
+		}
+		err := internal.Unmarshal(&jsonConfig, lookupInMap(jsonEnv), &s)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+		if s.Feature == nil || s.Feature.Mode != "safe" ||
+			!reflect.DeepEqual(s.Feature.Flags, []string{"beta"}) {
+			t.Errorf("unexpected feature config %+v", s.Feature)
+		}
+		if s.Limits["cpu"] != 2 || s.Raw != `{"cpu": 2}` {
+			t.Errorf("unexpected limits %v and %s", s.Limits, s.Raw)
+		}
+
+		var mismatched struct {
+			Limits []int `env:"LIMITS"`
+		}
+		err = internal.Unmarshal(&jsonConfig, lookupInMap(jsonEnv), &mismatched)
+		if err == nil || !strings.Contains(err.Error(), "var LIMITS: JSON does not fit []int") {
+			t.Errorf("expected decoding error, got \"%v\"", err)
+		}
+	})
+
+	t.Run("Only embedded and prefixed structs are decoded", func(t *testing.T) {
+		type node struct {
+			Name string `env:"NAME"`
+			Next *node
+		}
+		type base struct {
+			Port int `env:"PORT"`
+		}
+
+		var s struct {
+			base
+			Node     node
+			Endpoint url.URL
+			Limits   *cache
+			Cache    *cache    `envPrefix:"UNSET_"`
+			DB       *database `envPrefix:"DB_"`
+		}
+		prefixConfig := internal.Config{
+			Vars: append(slices.Clone(config.Vars), internal.VarEntry{
+				Name: "UNSET_SIZE",
+				Type: internal.IntType,
+			}),
+		}
+		err := internal.Unmarshal(&prefixConfig, lookupInMap(env), &s)
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		if s.Port != 8080 {
+			t.Errorf("expected embedded field to be 8080, got %d", s.Port)
+		}
+		if s.Node != (node{}) || s.Endpoint != (url.URL{}) || s.Limits != nil {
+			t.Errorf("fields without tags must not be changed, got %+v %+v %+v",
+				s.Node, s.Endpoint, s.Limits)
+		}
+		if s.Cache != nil {
+			t.Errorf("expected pointer without set vars to stay nil, got %+v", s.Cache)
+		}
+		if s.DB == nil || s.DB.Host != "db" {
+			t.Errorf("expected pointer with set vars to be allocated, got %+v", s.DB)
+		}
+	})
+
+	t.Run("Invalid struct", func(t *testing.T) {
+		type node struct {
+			Name string `env:"NAME"`
+			Next *node  `envPrefix:"NEXT_"`
+		}
+
+		testCases := []struct {
+			name              string
+			target            any
+			expectedErrorText string
+		}{
+			{
+				name:              "Not a pointer",
+				target:            settings{},
+				expectedErrorText: "expected non-nil pointer to struct, got internal_test.settings",
+			},
+			{
+				name: "Undeclared var",
+				target: &struct {
+					Missing string `env:"MISSING"`
+				}{},
+				expectedErrorText: "field Missing: var MISSING is not declared in schema",
+			},
+			{
+				name: "Type mismatch",
+				target: &struct {
+					DB struct {
+						Port string `env:"PORT"`
+					} `envPrefix:"DB_"`
+				}{},
+				expectedErrorText: "field DB.Port: type string does not match var DB_PORT",
+			},
+			{
+				name: "Slice of int var",
+				target: &struct {
+					Port []int `env:"PORT"`
+				}{},
+				expectedErrorText: "field Port: type []int does not match var PORT of type int",
+			},
+			{
+				name:              "Type nested in itself",
+				target:            &node{},
+				expectedErrorText: "field Next: type internal_test.node is nested in itself",
+			},
+			{
+				name: "Prefix of a field which is not a struct",
+				target: &struct {
+					Name string `envPrefix:"APP_"`
+				}{},
+				expectedErrorText: "field Name: envPrefix is set, but string is not a struct",
+			},
+			{
+				name: "Value overflows field",
+				target: &struct {
+					Port int8 `env:"PORT"`
+				}{},
+				expectedErrorText: "field Port: var PORT: \"8080\" is not a valid int8",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				err := internal.Unmarshal(&config, lookupInMap(env), tc.target)
+				if err == nil {
+					t.Fatal("expected to get an error")
+				}
+
+				if !strings.Contains(err.Error(), tc.expectedErrorText) {
+					t.Fatalf("expected \"%s\" to contain \"%s\"", err.Error(), tc.expectedErrorText)
+				}
+			})
+		}
+	})
+}
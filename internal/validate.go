@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+type ValidateOptions struct {
+	// Stop at the first stage which fails instead of running all checks
+	FailFast bool
+	// Sources of var values, used to report file and line in errors
+	Sources map[string]VarSource
+}
+
+/*
+This function validates env against c without modifying env or os.env. Unset vars are populated
+with their defaults in a copy of env, then required vars, types, patterns and allowed values are
+checked.
+The copy is returned, so it holds the values which the checks were done on. It is returned also
+when validation fails, together with the error.
+
+All checks are run and problems are reported at once, as one VarError per failed var. If
+o.FailFast is set, checks are run stage by stage and validation stops at the first stage which
+fails. Either way the problems are ValidationError values which can be found with errors.As.
+*/
+func Validate(c *Config, env map[string]string, o ValidateOptions) (map[string]string, error) {
+	resolved := maps.Clone(env)
+	if resolved == nil {
+		resolved = make(map[string]string)
+	}
+
+	PopulateUnsetVarsWithDefaults(c, resolved)
+
+	if o.FailFast {
+		if err := validateStages(c, resolved); err != nil {
+			attachSources(err, o.Sources)
+			return resolved, err
+		}
+		return resolved, nil
+	}
+
+	var varErrors []error
+	for _, v := range c.Vars {
+		if err := checkVar(v, resolved); err != nil {
+			varErrors = append(varErrors, err)
+		}
+	}
+	if len(varErrors) > 0 {
+		header := fmt.Errorf("%d of %d vars failed validation", len(varErrors), len(c.Vars))
+		err := errors.Join(append([]error{header}, varErrors...)...)
+		attachSources(err, o.Sources)
+		return resolved, err
+	}
+
+	return resolved, nil
+}
+
+// VarError holds all problems found in one var
+type VarError struct {
+	Name string
+	Errs []error
+}
+
+func (e *VarError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Name + ":")
+	for _, err := range e.Errs {
+		b.WriteString("\n\t" + strings.ReplaceAll(err.Error(), "\n", "\n\t"))
+	}
+	return b.String()
+}
+
+func (e *VarError) Unwrap() []error {
+	return e.Errs
+}
+
+// checkVar runs all checks of var v, nil is returned if it passes them
+func checkVar(v VarEntry, env map[string]string) *VarError {
+	var errs []error
+
+	value, isSet := env[v.Name]
+	if !isSet {
+		if err := checkRequired(v, env); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		if err := checkType(v, value); err != nil {
+			errs = append(errs, err)
+		}
+		if err := checkPattern(v, value); err != nil {
+			errs = append(errs, err)
+		}
+		if err := checkAllowedValues(v, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &VarError{Name: v.Name, Errs: errs}
+}
+
+// validateStages runs checks stage by stage and stops at the first stage which fails
+func validateStages(c *Config, env map[string]string) error {
+	if err := ValidateRequired(c, env); err != nil {
+		return errors.Join(errors.New("failed to validate required vars"), err)
+	}
+
+	if err := ValidateTypes(c, env); err != nil {
+		return errors.Join(errors.New("failed to validate var types"), err)
+	}
+
+	if err := ValidatePatterns(c, env); err != nil {
+		return errors.Join(errors.New("failed to validate var patterns"), err)
+	}
+
+	if err := ValidateAllowedValues(c, env); err != nil {
+		return errors.Join(errors.New("failed to validate allowed values"), err)
+	}
+
+	return nil
+}
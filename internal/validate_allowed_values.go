@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"errors"
+	"slices"
+)
+
+/*
+If var.AllowedValues is not empty, value of the var must be one of them.
+*/
+func ValidateAllowedValues(c *Config, env map[string]string) error {
+	var allowedError error = nil
+
+	for _, v := range c.Vars {
+		value, ok := env[v.Name]
+		if !ok {
+			continue
+		}
+
+		if err := checkAllowedValues(v, value); err != nil {
+			allowedError = errors.Join(err, allowedError)
+		}
+	}
+
+	return allowedError
+}
+
+func checkAllowedValues(v VarEntry, value string) error {
+	if len(v.AllowedValues) == 0 || slices.Contains(v.AllowedValues, value) {
+		return nil
+	}
+
+	return &AllowedValuesError{
+		ErrorDetails: ErrorDetails{
+			Var:   v.Name,
+			Rule:  "allowed_values",
+			Value: reportedValue(v, value),
+		},
+		AllowedValues: v.AllowedValues,
+	}
+}
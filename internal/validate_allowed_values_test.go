@@ -0,0 +1,45 @@
+package internal_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestValidateAllowedValues(t *testing.T) {
+	config := internal.Config{
+		Vars: []internal.VarEntry{
+			{Name: "MODE", Type: internal.StringType, AllowedValues: []string{"dev", "prod"}},
+			{Name: "LEVEL", Type: internal.IntType, AllowedValues: []string{"1", "2"}},
+			{Name: "NAME", Type: internal.StringType},
+		},
+	}
+
+	t.Run("Allowed values", func(t *testing.T) {
+		env := map[string]string{"MODE": "prod", "LEVEL": "2", "NAME": "anything"}
+
+		if err := internal.ValidateAllowedValues(&config, env); err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+	})
+
+	t.Run("Not allowed values", func(t *testing.T) {
+		env := map[string]string{"MODE": "test", "LEVEL": "3"}
+
+		err := internal.ValidateAllowedValues(&config, env)
+		if err == nil {
+			t.Fatal("expected to get an error")
+		}
+
+		expectedErrorTexts := []string{
+			"var MODE is not one of allowed values dev, prod",
+			"var LEVEL is not one of allowed values 1, 2",
+		}
+		for _, expectedErrorText := range expectedErrorTexts {
+			if !strings.Contains(err.Error(), expectedErrorText) {
+				t.Errorf("expected \"%s\" to contain \"%s\"", err.Error(), expectedErrorText)
+			}
+		}
+	})
+}
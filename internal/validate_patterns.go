@@ -2,52 +2,47 @@ package internal
 
 import (
 	"errors"
-	"fmt"
-	"os"
 	"regexp"
 )
 
 /*
 If var.Pattern is not empty, it is a regex against which var value should be checked.
 */
-func ValidatePatterns(c *Config) error {
+func ValidatePatterns(c *Config, env map[string]string) error {
 	var patternError error = nil
 
 	for _, v := range c.Vars {
-		if v.Pattern == nil {
-			continue
-		}
-
-		value, ok := os.LookupEnv(v.Name)
+		value, ok := env[v.Name]
 		if !ok {
 			// If the variable is not set, skip the validation
 			continue
 		}
 
-		if v.Type != StringType {
-			return fmt.Errorf(
-				"variable \"%s\" has type %s, \"pattern\" is supported only for type %s",
-				v.Name,
-				v.Type,
-				StringType,
-			)
-		}
-
-		// Check if the value matches the pattern
-		matched, err := regexp.MatchString(*v.Pattern, value)
-		if err != nil {
-			patternError = errors.Join(
-				fmt.Errorf("failed to compile regex for variable %s: %w", v.Name, err),
-				patternError,
-			)
-		}
-		if !matched {
-			patternError = errors.Join(
-				fmt.Errorf("variable %s does not match pattern %v", v.Name, *v.Pattern),
-				patternError,
-			)
+		if err := checkPattern(v, value); err != nil {
+			patternError = errors.Join(err, patternError)
 		}
 	}
 
 	return patternError
 }
+
+func checkPattern(v VarEntry, value string) error {
+	if v.Pattern == nil {
+		return nil
+	}
+
+	matched, err := regexp.MatchString(*v.Pattern, value)
+	if err != nil || v.Type != StringType {
+		// The schema is invalid, which is normally found by CheckSchema before
+		err := checkPatternOptions(v)
+		if schemaErr, ok := err.(*SchemaError); ok {
+			schemaErr.Value = reportedValue(v, value)
+		}
+		return err
+	}
+	if !matched {
+		details := ErrorDetails{Var: v.Name, Rule: "pattern", Value: reportedValue(v, value)}
+		return &PatternError{ErrorDetails: details, Pattern: *v.Pattern}
+	}
+	return nil
+}
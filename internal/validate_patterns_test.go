@@ -1,7 +1,6 @@
 package internal_test
 
 import (
-	"os"
 	"strings"
 	"testing"
 
@@ -11,9 +10,10 @@ import (
 func testValidatePatternsWithError(
 	t *testing.T,
 	config *internal.Config,
+	env map[string]string,
 	expectedErrorText string,
 ) {
-	err := internal.ValidatePatterns(config)
+	err := internal.ValidatePatterns(config, env)
 	if err == nil {
 		t.Fatalf("expected to get an error")
 	}
@@ -25,10 +25,6 @@ func testValidatePatternsWithError(
 
 func TestValidatePatterns(t *testing.T) {
 	t.Run("Correct env", func(t *testing.T) {
-		keys := []string{"firstVar"}
-		originalEnv := saveAndClearEnv(keys)
-		defer restoreEnv(originalEnv)
-
 		config := internal.Config{
 			Vars: []internal.VarEntry{
 				{
@@ -39,21 +35,15 @@ func TestValidatePatterns(t *testing.T) {
 			},
 		}
 
-		if err := os.Setenv("firstVar", "123"); err != nil {
-			t.Fatal("failed to set env firstVar")
-		}
+		env := map[string]string{"firstVar": "123"}
 
-		err := internal.ValidatePatterns(&config)
+		err := internal.ValidatePatterns(&config, env)
 		if err != nil {
 			t.Fatalf("unexpected error \"%v\"", err)
 		}
 	})
 
 	t.Run("Incorrect env", func(t *testing.T) {
-		keys := []string{"firstVar"}
-		originalEnv := saveAndClearEnv(keys)
-		defer restoreEnv(originalEnv)
-
 		config := internal.Config{
 			Vars: []internal.VarEntry{
 				{
@@ -64,20 +54,14 @@ func TestValidatePatterns(t *testing.T) {
 			},
 		}
 
-		if err := os.Setenv("firstVar", "6581567231"); err != nil {
-			t.Fatal("failed to set env firstVar")
-		}
+		env := map[string]string{"firstVar": "6581567231"}
 
 		const expectedErrorText = "variable firstVar does not match pattern [01]..$"
 
-		testValidatePatternsWithError(t, &config, expectedErrorText)
+		testValidatePatternsWithError(t, &config, env, expectedErrorText)
 	})
 
 	t.Run("Incorrect pattern", func(t *testing.T) {
-		keys := []string{"firstVar"}
-		originalEnv := saveAndClearEnv(keys)
-		defer restoreEnv(originalEnv)
-
 		config := internal.Config{
 			Vars: []internal.VarEntry{
 				{
@@ -88,20 +72,14 @@ func TestValidatePatterns(t *testing.T) {
 			},
 		}
 
-		if err := os.Setenv("firstVar", "i164187s"); err != nil {
-			t.Fatal("failed to set env firstVar")
-		}
+		env := map[string]string{"firstVar": "i164187s"}
 
 		const expectedErrorText = "failed to compile regex for variable firstVar"
 
-		testValidatePatternsWithError(t, &config, expectedErrorText)
+		testValidatePatternsWithError(t, &config, env, expectedErrorText)
 	})
 
 	t.Run("Incorrect var type", func(t *testing.T) {
-		keys := []string{"firstVar"}
-		originalEnv := saveAndClearEnv(keys)
-		defer restoreEnv(originalEnv)
-
 		config := internal.Config{
 			Vars: []internal.VarEntry{
 				{
@@ -112,12 +90,24 @@ func TestValidatePatterns(t *testing.T) {
 			},
 		}
 
-		if err := os.Setenv("firstVar", "124"); err != nil {
-			t.Fatal("failed to set env firstVar")
-		}
+		env := map[string]string{"firstVar": "124"}
 
 		const expectedErrorText = "variable \"firstVar\" has type int, \"pattern\" is supported only for type string"
 
-		testValidatePatternsWithError(t, &config, expectedErrorText)
+		testValidatePatternsWithError(t, &config, env, expectedErrorText)
+	})
+
+	t.Run("Incorrect var type does not stop validation", func(t *testing.T) {
+		config := internal.Config{
+			Vars: []internal.VarEntry{
+				{Name: "firstVar", Type: "int", Pattern: StringPtr("[01]..$")},
+				{Name: "secondVar", Type: "string", Pattern: StringPtr("[01]..$")},
+			},
+		}
+
+		env := map[string]string{"firstVar": "124", "secondVar": "999"}
+
+		testValidatePatternsWithError(t, &config, env, "variable \"firstVar\" has type int")
+		testValidatePatternsWithError(t, &config, env, "variable secondVar does not match pattern")
 	})
 }
@@ -2,24 +2,24 @@ package internal
 
 import (
 	"errors"
-	"fmt"
-	"os"
 )
 
 // Validate required vars
-func ValidateRequired(c *Config) error {
+func ValidateRequired(c *Config, env map[string]string) error {
 	var requireError error = nil
 
 	for _, v := range c.Vars {
-		if v.Required {
-			if _, ok := os.LookupEnv(v.Name); !ok {
-				requireError = errors.Join(
-					fmt.Errorf("required var %s is not set", v.Name),
-					requireError,
-				)
-			}
+		if err := checkRequired(v, env); err != nil {
+			requireError = errors.Join(err, requireError)
 		}
 	}
 
 	return requireError
 }
+
+func checkRequired(v VarEntry, env map[string]string) error {
+	if _, ok := env[v.Name]; v.Required && !ok {
+		return &MissingError{ErrorDetails: ErrorDetails{Var: v.Name, Rule: "required"}}
+	}
+	return nil
+}
@@ -1,7 +1,6 @@
 package internal_test
 
 import (
-	"os"
 	"strings"
 	"testing"
 
@@ -24,24 +23,20 @@ func TestValidateRequired(t *testing.T) {
 		}
 
 		t.Run("Required vars are set", func(t *testing.T) {
-			if err := os.Setenv("secondVar", "123"); err != nil {
-				t.Fatal("failed to set env secondVar")
-			}
+			env := map[string]string{"secondVar": "123"}
 
-			err := internal.ValidateRequired(&config)
+			err := internal.ValidateRequired(&config, env)
 			if err != nil {
 				t.Fatalf("unexpected error \"%v\"", err)
 			}
 		})
 
 		t.Run("Required vars are not set", func(t *testing.T) {
-			if err := os.Unsetenv("secondVar"); err != nil {
-				t.Fatal("failed to unset env secondVar")
-			}
+			env := map[string]string{"firstVar": "123"}
 
 			const expectedErrorText = "required var secondVar is not set"
 
-			err := internal.ValidateRequired(&config)
+			err := internal.ValidateRequired(&config, env)
 			if err == nil {
 				t.Fatal("expected to get an error")
 			}
@@ -66,15 +61,9 @@ func TestValidateRequired(t *testing.T) {
 		}
 
 		t.Run("Required vars are set", func(t *testing.T) {
-			if err := os.Setenv("secondVar", "123"); err != nil {
-				t.Fatal("failed to set env secondVar")
-			}
-
-			if err := os.Unsetenv("firstVar"); err != nil {
-				t.Fatal("failed to unset env firstVar")
-			}
+			env := map[string]string{"secondVar": "123"}
 
-			err := internal.ValidateRequired(&config)
+			err := internal.ValidateRequired(&config, env)
 			if err != nil {
 				t.Fatalf("unexpected error \"%v\"", err)
 			}
@@ -0,0 +1,139 @@
+package internal_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestValidate(t *testing.T) {
+	config := internal.Config{
+		Vars: []internal.VarEntry{
+			{
+				Name:     "HOST",
+				Required: true,
+				Type:     internal.StringType,
+				Pattern:  StringPtr("^[a-z.]+$"),
+			},
+			{Name: "PORT", Required: true, Type: internal.IntType, DefaultValue: 8080},
+			{Name: "DEBUG", Type: internal.BoolType},
+		},
+	}
+
+	t.Run("Valid env", func(t *testing.T) {
+		env := map[string]string{"HOST": "localhost", "OTHER": "kept"}
+
+		resolved, err := internal.Validate(&config, env, internal.ValidateOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error \"%v\"", err)
+		}
+
+		expected := map[string]string{"HOST": "localhost", "PORT": "8080", "OTHER": "kept"}
+		if !reflect.DeepEqual(resolved, expected) {
+			t.Errorf("expected: %v\n Actual: %v", expected, resolved)
+		}
+
+		if _, isSet := env["PORT"]; isSet {
+			t.Error("env passed to Validate must not be modified")
+		}
+	})
+
+	t.Run("Invalid env", func(t *testing.T) {
+		testCases := []struct {
+			name              string
+			env               map[string]string
+			expectedErrorText string
+		}{
+			{
+				name:              "Required var is not set",
+				env:               nil,
+				expectedErrorText: "required var HOST is not set",
+			},
+			{
+				name:              "Invalid type",
+				env:               map[string]string{"HOST": "localhost", "DEBUG": "maybe"},
+				expectedErrorText: "var DEBUG is not a valid bool",
+			},
+			{
+				name:              "Pattern mismatch",
+				env:               map[string]string{"HOST": "LOCALHOST"},
+				expectedErrorText: "variable HOST does not match pattern",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := internal.Validate(&config, tc.env, internal.ValidateOptions{})
+				if err == nil {
+					t.Fatal("expected to get an error")
+				}
+
+				if !strings.Contains(err.Error(), tc.expectedErrorText) {
+					t.Fatalf("expected \"%s\" to contain \"%s\"", err.Error(), tc.expectedErrorText)
+				}
+			})
+		}
+	})
+
+	t.Run("All problems are reported per var", func(t *testing.T) {
+		env := map[string]string{"PORT": "http", "DEBUG": "maybe"}
+
+		_, err := internal.Validate(&config, env, internal.ValidateOptions{})
+		if err == nil {
+			t.Fatal("expected to get an error")
+		}
+
+		expectedErrorTexts := []string{
+			"3 of 3 vars failed validation",
+			"HOST:\n\trequired var HOST is not set",
+			"PORT:\n\tvar PORT is not a valid int",
+			"DEBUG:\n\tvar DEBUG is not a valid bool",
+		}
+		for _, expectedErrorText := range expectedErrorTexts {
+			if !strings.Contains(err.Error(), expectedErrorText) {
+				t.Errorf("expected \"%s\" to contain \"%s\"", err.Error(), expectedErrorText)
+			}
+		}
+
+		var varError *internal.VarError
+		if !errors.As(err, &varError) || varError.Name != "HOST" {
+			t.Errorf("expected to find VarError of HOST in \"%v\"", err)
+		}
+	})
+
+	t.Run("Resolved values are returned with error", func(t *testing.T) {
+		env := map[string]string{"HOST": "LOCALHOST", "DEBUG": "maybe"}
+		expected := map[string]string{"HOST": "LOCALHOST", "PORT": "8080", "DEBUG": "maybe"}
+
+		for _, failFast := range []bool{false, true} {
+			resolved, err := internal.Validate(&config, env, internal.ValidateOptions{
+				FailFast: failFast,
+			})
+			if err == nil {
+				t.Fatal("expected to get an error")
+			}
+			if !reflect.DeepEqual(resolved, expected) {
+				t.Errorf("expected: %v\n Actual: %v", expected, resolved)
+			}
+		}
+	})
+
+	t.Run("Fail fast", func(t *testing.T) {
+		env := map[string]string{"PORT": "http", "DEBUG": "maybe"}
+
+		_, err := internal.Validate(&config, env, internal.ValidateOptions{FailFast: true})
+		if err == nil {
+			t.Fatal("expected to get an error")
+		}
+
+		if !strings.Contains(err.Error(), "failed to validate required vars") {
+			t.Fatalf("expected \"%s\" to report required vars", err.Error())
+		}
+		if strings.Contains(err.Error(), "is not a valid") {
+			t.Fatalf("expected \"%s\" not to report types", err.Error())
+		}
+	})
+}
@@ -3,39 +3,167 @@ package internal
 import (
 	"errors"
 	"fmt"
-	"os"
 	"strconv"
+	"strings"
 )
 
-func ValidateTypes(c *Config) error {
+/*
+This function converts value of var v to the Go type matching v.Type:
+string and any vars give string, int gives int, float gives float64, bool gives bool.
+Network types give *url.URL for url, HostPort for hostport, netip.Addr for ip, netip.Prefix for
+cidr and int for port. Time types give time.Duration for duration, time.Time for time,
+*time.Location for timezone and CronSchedule for cron. size gives int64 bytes and quantity gives
+float64 in the unit of the var. list gives []any and map gives map[string]any, with items
+converted according to the element of the var. json gives the value decoded by encoding/json.
+Options of v are expected to be checked by CheckSchema before.
+*/
+func ParseValue(v VarEntry, value string) (any, error) {
+	switch v.Type {
+	case StringType, AnyType:
+		return value, nil // anything can be a string
+	case IntType:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, typeError(v, value)
+		}
+		return i, nil
+	case FloatType:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, typeError(v, value)
+		}
+		return f, nil
+	case BoolType:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, typeError(v, value)
+		}
+		return b, nil
+	case URLType:
+		return parseURL(v, value)
+	case HostPortType:
+		return parseHostPort(v, value)
+	case IPType:
+		return parseIP(v, value)
+	case CIDRType:
+		return parseCIDR(v, value)
+	case PortType:
+		return parsePort(v, value)
+	case DurationType:
+		return parseDuration(v, value)
+	case TimeType:
+		return parseTime(v, value)
+	case TimezoneType:
+		return parseTimezone(v, value)
+	case CronType:
+		return parseCron(v, value)
+	case SizeType:
+		return parseSize(v, value)
+	case QuantityType:
+		return parseQuantityValue(v, value)
+	case ListType:
+		return parseList(v, value)
+	case MapType:
+		return parseMap(v, value)
+	case JSONType:
+		return parseJSON(v, value)
+	default:
+		return nil, &SchemaError{
+			ErrorDetails: ErrorDetails{Var: v.Name, Rule: "type", Value: reportedValue(v, value)},
+			Message:      fmt.Sprintf("var %s has an unsupported type", v.Name),
+		}
+	}
+}
+
+func typeError(v VarEntry, value string) *TypeError {
+	return &TypeError{
+		ErrorDetails: ErrorDetails{Var: v.Name, Rule: "type", Value: reportedValue(v, value)},
+		Type:         v.Type,
+	}
+}
+
+// typeErrorWithReason is typeError which tells what is wrong with value
+func typeErrorWithReason(v VarEntry, value, reason string) *TypeError {
+	err := typeError(v, value)
+	err.Reason = reason
+	return err
+}
+
+func ValidateTypes(c *Config, env map[string]string) error {
 	var typeError error = nil
 
 	for _, v := range c.Vars {
-		value, ok := os.LookupEnv(v.Name)
+		value, ok := env[v.Name]
 		if !ok {
 			continue // unset vars do not have type. Wether they are allowed to be unset is handled in ValidateRequiredVars
 		}
 
-		if v.Type == StringType {
-			continue // anything can be a string
-		} else if v.Type == IntType {
-			if _, err := strconv.Atoi(value); err != nil {
-				typeError = errors.Join(fmt.Errorf("var %s is not a valid int", v.Name), typeError)
-			}
-		} else if v.Type == FloatType {
-			if _, err := strconv.ParseFloat(value, 64); err != nil {
-				typeError = errors.Join(fmt.Errorf("var %s is not a valid float", v.Name), typeError)
-			}
-		} else if v.Type == BoolType {
-			if _, err := strconv.ParseBool(value); err != nil {
-				typeError = errors.Join(fmt.Errorf("var %s is not a valid bool", v.Name), typeError)
-			}
-		} else if v.Type == AnyType {
-			continue
-		} else {
-			typeError = errors.Join(fmt.Errorf("var %s has an unsupported type", v.Name), typeError)
+		if err := checkType(v, value); err != nil {
+			typeError = errors.Join(err, typeError)
 		}
 	}
 
 	return typeError
 }
+
+func checkType(v VarEntry, value string) error {
+	_, err := ParseValue(v, value)
+	return err
+}
+
+/*
+typeSummary describes type of v with its options, like "url (http, https, with host)" or
+"list of port (at most 3 items, unique)".
+*/
+func typeSummary(v VarEntry) string {
+	name := string(v.Type)
+	if v.Type == ListType || v.Type == MapType {
+		name += " of " + typeSummary(elementEntry(v))
+	}
+
+	var options []string
+	options = append(options, v.Schemes...)
+	if v.RequireHost {
+		options = append(options, "with host")
+	}
+	if v.IPVersion != 0 {
+		options = append(options, fmt.Sprintf("IPv%d", v.IPVersion))
+	}
+	if v.Min != "" {
+		options = append(options, "min "+v.Min)
+	}
+	if v.Max != "" {
+		options = append(options, "max "+v.Max)
+	}
+	if v.Layout != "" {
+		options = append(options, "layout "+v.Layout)
+	}
+	if v.Type == QuantityType {
+		options = append(options, "in "+strings.Join(quantityUnits(v), " | "))
+	}
+	if v.Separator != "" {
+		options = append(options, fmt.Sprintf("separated by %q", v.Separator))
+	}
+	if v.KeyValueSeparator != "" {
+		options = append(options, fmt.Sprintf("key and value separated by %q", v.KeyValueSeparator))
+	}
+	if v.MinItems != 0 {
+		options = append(options, fmt.Sprintf("at least %d items", v.MinItems))
+	}
+	if v.MaxItems != 0 {
+		options = append(options, fmt.Sprintf("at most %d items", v.MaxItems))
+	}
+	if v.Unique {
+		options = append(options, "unique")
+	}
+	if v.JSONSchema != nil && v.JSONSchema.Type != "" {
+		options = append(options, v.JSONSchema.Type+" schema")
+	} else if v.JSONSchema != nil {
+		options = append(options, "with schema")
+	}
+
+	if len(options) == 0 {
+		return name
+	}
+	return name + " (" + strings.Join(options, ", ") + ")"
+}
@@ -1,7 +1,6 @@
 package internal_test
 
 import (
-	"os"
 	"strings"
 	"testing"
 
@@ -9,8 +8,12 @@ import (
 )
 
 func TestValidateTypes(t *testing.T) {
-	testWithError := func(config *internal.Config, expectedErrorText string) {
-		err := internal.ValidateTypes(config)
+	testWithError := func(
+		config *internal.Config,
+		env map[string]string,
+		expectedErrorText string,
+	) {
+		err := internal.ValidateTypes(config, env)
 		if err == nil {
 			t.Fatal("expected to get an error")
 		}
@@ -54,31 +57,16 @@ func TestValidateTypes(t *testing.T) {
 			},
 		}
 
-		if err := os.Setenv("stringVar", "testString"); err != nil {
-			t.Fatal("failed to set env stringVar")
+		env := map[string]string{
+			"stringVar": "testString",
+			"intVar":    "1234",
+			"floatVar":  "-27.917",
+			"floatVar2": "0",
+			"boolVar":   "false",
+			"anyVar":    "{[***]}{.,.,~!@#$%^&*()}",
 		}
 
-		if err := os.Setenv("intVar", "1234"); err != nil {
-			t.Fatal("failed to set env intVar")
-		}
-
-		if err := os.Setenv("floatVar", "-27.917"); err != nil {
-			t.Fatal("failed to set env floatVar")
-		}
-
-		if err := os.Setenv("floatVar2", "0"); err != nil {
-			t.Fatal("failed to set env floatVar2")
-		}
-
-		if err := os.Setenv("boolVar", "false"); err != nil {
-			t.Fatal("failed to set env boolVar")
-		}
-
-		if err := os.Setenv("anyVar", "{[***]}{.,.,~!@#$%^&*()}"); err != nil {
-			t.Fatal("failled to set env anyVar")
-		}
-
-		err := internal.ValidateTypes(&config)
+		err := internal.ValidateTypes(&config, env)
 		if err != nil {
 			t.Fatalf("unexpected error \"%v\"", err)
 		}
@@ -95,13 +83,11 @@ func TestValidateTypes(t *testing.T) {
 				},
 			}
 
-			if err := os.Setenv("intVar", "123def45"); err != nil {
-				t.Fatal("failed to set env intVar")
-			}
+			env := map[string]string{"intVar": "123def45"}
 
 			const expectedErrorText = "var intVar is not a valid int"
 
-			testWithError(&config, expectedErrorText)
+			testWithError(&config, env, expectedErrorText)
 		})
 
 		t.Run("Incorrect float", func(t *testing.T) {
@@ -114,13 +100,11 @@ func TestValidateTypes(t *testing.T) {
 				},
 			}
 
-			if err := os.Setenv("floatVar", "123def45"); err != nil {
-				t.Fatal("failed to set env floatVar")
-			}
+			env := map[string]string{"floatVar": "123def45"}
 
 			const expectedErrorText = "var floatVar is not a valid float"
 
-			testWithError(&config, expectedErrorText)
+			testWithError(&config, env, expectedErrorText)
 		})
 
 		t.Run("Incorrect bool", func(t *testing.T) {
@@ -133,13 +117,11 @@ func TestValidateTypes(t *testing.T) {
 				},
 			}
 
-			if err := os.Setenv("boolVar", "123def45"); err != nil {
-				t.Fatal("failed to set env boolVar")
-			}
+			env := map[string]string{"boolVar": "123def45"}
 
 			const expectedErrorText = "var boolVar is not a valid bool"
 
-			testWithError(&config, expectedErrorText)
+			testWithError(&config, env, expectedErrorText)
 		})
 
 		t.Run("Unsupported type", func(t *testing.T) {
@@ -152,13 +134,38 @@ func TestValidateTypes(t *testing.T) {
 				},
 			}
 
-			if err := os.Setenv("strangeVar", "123def45"); err != nil {
-				t.Fatal("failed to set env strangeVar")
-			}
+			env := map[string]string{"strangeVar": "123def45"}
 
 			const expectedErrorText = "var strangeVar has an unsupported type"
 
-			testWithError(&config, expectedErrorText)
+			testWithError(&config, env, expectedErrorText)
 		})
 	})
 }
+
+func TestParseValue(t *testing.T) {
+	testCases := []struct {
+		entry    internal.VarEntry
+		value    string
+		expected any
+	}{
+		{internal.VarEntry{Name: "s", Type: internal.StringType}, "text", "text"},
+		{internal.VarEntry{Name: "a", Type: internal.AnyType}, "{}", "{}"},
+		{internal.VarEntry{Name: "i", Type: internal.IntType}, "-12", -12},
+		{internal.VarEntry{Name: "f", Type: internal.FloatType}, "1.5", 1.5},
+		{internal.VarEntry{Name: "b", Type: internal.BoolType}, "1", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.entry.Type), func(t *testing.T) {
+			parsed, err := internal.ParseValue(tc.entry, tc.value)
+			if err != nil {
+				t.Fatalf("unexpected error \"%v\"", err)
+			}
+
+			if parsed != tc.expected {
+				t.Errorf("expected %v (%T), got %v (%T)", tc.expected, tc.expected, parsed, parsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Codes of validation errors. They are stable, so tools may rely on them.
+const (
+	CodeMissing = "ENVCHECK_MISSING"
+	CodeType    = "ENVCHECK_TYPE"
+	CodePattern = "ENVCHECK_PATTERN"
+	CodeSchema  = "ENVCHECK_SCHEMA"
+	CodeAllowed = "ENVCHECK_ALLOWED_VALUES"
+)
+
+// RedactedValue is reported instead of values of secret vars
+const RedactedValue = "[redacted]"
+
+// ValidationError is implemented by all errors which validation of a var may produce
+type ValidationError interface {
+	error
+	Code() string
+	Details() ErrorDetails
+}
+
+// ErrorDetails are fields common to all validation errors
+type ErrorDetails struct {
+	Var string
+	// Rule which failed: "required", "type", "pattern", "allowed_values", "json_schema" or the
+	// schema field which is invalid
+	Rule string
+	// Offending value, RedactedValue if the var is secret, "" if the var is not set
+	Value string
+	// Location of the definition in .env file, empty if the value did not come from a file
+	File string
+	Line int
+	// Index of the list item or key of the map entry which failed, empty if the whole value did
+	Element string
+	// JSON pointer of the node of a json value which failed, empty if the whole value did
+	Pointer string
+}
+
+// Details returns the fields common to all validation errors
+func (d *ErrorDetails) Details() ErrorDetails {
+	return *d
+}
+
+func (d *ErrorDetails) errorDetails() *ErrorDetails {
+	return d
+}
+
+func (d *ErrorDetails) setSource(source VarSource) {
+	d.File = source.File
+	d.Line = source.Line
+}
+
+/*
+subject returns the var name with the failed element and JSON node if there are ones, like
+VAR[2] or VAR at /servers/0/port.
+*/
+func (d *ErrorDetails) subject() string {
+	subject := d.Var
+	if d.Element != "" {
+		subject += "[" + d.Element + "]"
+	}
+	if d.Pointer != "" {
+		subject += " at " + d.Pointer
+	}
+	return subject
+}
+
+// location returns " (file:line)" if the value came from a file
+func (d *ErrorDetails) location() string {
+	if d.File == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s:%d)", d.File, d.Line)
+}
+
+// MissingError means that a required var is not set and has no default value
+type MissingError struct {
+	ErrorDetails
+}
+
+func (e *MissingError) Code() string {
+	return CodeMissing
+}
+
+func (e *MissingError) Error() string {
+	return fmt.Sprintf("required var %s is not set", e.Var)
+}
+
+// TypeError means that value of a var can not be parsed as its declared type
+type TypeError struct {
+	ErrorDetails
+	Type SupportedVarType
+	// What is wrong with the value, empty if it just can not be parsed
+	Reason string
+}
+
+func (e *TypeError) Code() string {
+	return CodeType
+}
+
+func (e *TypeError) Error() string {
+	reason := ""
+	if e.Reason != "" {
+		reason = ": " + e.Reason
+	}
+	return fmt.Sprintf("var %s is not a valid %s%s%s", e.subject(), e.Type, reason, e.location())
+}
+
+// PatternError means that value of a var does not match its pattern
+type PatternError struct {
+	ErrorDetails
+	Pattern string
+}
+
+func (e *PatternError) Code() string {
+	return CodePattern
+}
+
+func (e *PatternError) Error() string {
+	return fmt.Sprintf(
+		"variable %s does not match pattern %v%s",
+		e.subject(),
+		e.Pattern,
+		e.location(),
+	)
+}
+
+// AllowedValuesError means that value of a var is not one of its allowed values
+type AllowedValuesError struct {
+	ErrorDetails
+	AllowedValues []string
+}
+
+func (e *AllowedValuesError) Code() string {
+	return CodeAllowed
+}
+
+func (e *AllowedValuesError) Error() string {
+	return fmt.Sprintf(
+		"var %s is not one of allowed values %s%s",
+		e.subject(),
+		strings.Join(e.AllowedValues, ", "),
+		e.location(),
+	)
+}
+
+// SchemaError means that the declaration of a var is invalid, so its value can not be checked
+type SchemaError struct {
+	ErrorDetails
+	Message string
+	Err     error
+}
+
+func (e *SchemaError) Code() string {
+	return CodeSchema
+}
+
+func (e *SchemaError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *SchemaError) Unwrap() error {
+	return e.Err
+}
+
+// reportedValue returns value of var v as it may be shown in errors
+func reportedValue(v VarEntry, value string) string {
+	if v.Secret {
+		return RedactedValue
+	}
+	return value
+}
+
+/*
+attachSources sets file and line of validation errors found in err according to the sources of
+their vars.
+*/
+func attachSources(err error, sources map[string]VarSource) {
+	forEachDetails(err, func(d *ErrorDetails) {
+		if source, ok := sources[d.Var]; ok {
+			d.setSource(source)
+		}
+	})
+}
+
+// markElement sets the failed element of validation errors found in err
+func markElement(err error, element string) {
+	forEachDetails(err, func(d *ErrorDetails) {
+		d.Element = element
+	})
+}
+
+// forEachDetails calls f with details of every validation error found in err
+func forEachDetails(err error, f func(d *ErrorDetails)) {
+	if e, ok := err.(interface {
+		ValidationError
+		errorDetails() *ErrorDetails
+	}); ok {
+		f(e.errorDetails())
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			forEachDetails(err, f)
+		}
+	case interface{ Unwrap() error }:
+		forEachDetails(e.Unwrap(), f)
+	}
+}
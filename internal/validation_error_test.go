@@ -0,0 +1,128 @@
+package internal_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+func TestValidationErrors(t *testing.T) {
+	config := internal.Config{
+		Vars: []internal.VarEntry{
+			{Name: "HOST", Required: true, Type: internal.StringType},
+			{Name: "PORT", Type: internal.IntType},
+			{Name: "TOKEN", Type: internal.StringType, Pattern: StringPtr("^tk_"), Secret: true},
+			{Name: "LEVEL", Type: internal.IntType, Pattern: StringPtr("^[0-9]$")},
+			{Name: "MODE", Type: internal.StringType, AllowedValues: []string{"dev", "prod"}},
+		},
+	}
+	env := map[string]string{"PORT": "http", "TOKEN": "s3cr3t", "LEVEL": "3", "MODE": "test"}
+	sources := map[string]internal.VarSource{"PORT": {File: ".env", Line: 2}}
+
+	modes := []struct {
+		name     string
+		failFast bool
+	}{
+		{name: "Collect all", failFast: false},
+		{name: "Fail fast", failFast: true},
+	}
+	for _, mode := range modes {
+		t.Run(mode.name, func(t *testing.T) {
+			_, err := internal.Validate(&config, env, internal.ValidateOptions{
+				FailFast: mode.failFast,
+				Sources:  sources,
+			})
+			if err == nil {
+				t.Fatal("expected to get an error")
+			}
+
+			var missing *internal.MissingError
+			if !errors.As(err, &missing) {
+				t.Fatalf("expected to find MissingError in \"%v\"", err)
+			}
+			expected := internal.ErrorDetails{Var: "HOST", Rule: "required"}
+			if missing.Code() != internal.CodeMissing || missing.Details() != expected {
+				t.Errorf("expected %s %+v, got %s %+v", internal.CodeMissing, expected,
+					missing.Code(), missing.Details())
+			}
+		})
+	}
+
+	_, err := internal.Validate(&config, env, internal.ValidateOptions{Sources: sources})
+	if err == nil {
+		t.Fatal("expected to get an error")
+	}
+
+	testCases := []struct {
+		name     string
+		target   internal.ValidationError
+		code     string
+		expected internal.ErrorDetails
+	}{
+		{
+			name:   "Type error with source",
+			target: &internal.TypeError{},
+			code:   internal.CodeType,
+			expected: internal.ErrorDetails{
+				Var:   "PORT",
+				Rule:  "type",
+				Value: "http",
+				File:  ".env",
+				Line:  2,
+			},
+		},
+		{
+			name:   "Pattern error of secret var",
+			target: &internal.PatternError{},
+			code:   internal.CodePattern,
+			expected: internal.ErrorDetails{
+				Var:   "TOKEN",
+				Rule:  "pattern",
+				Value: internal.RedactedValue,
+			},
+		},
+		{
+			name:     "Allowed values error",
+			target:   &internal.AllowedValuesError{},
+			code:     internal.CodeAllowed,
+			expected: internal.ErrorDetails{Var: "MODE", Rule: "allowed_values", Value: "test"},
+		},
+		{
+			name:     "Schema error",
+			target:   &internal.SchemaError{},
+			code:     internal.CodeSchema,
+			expected: internal.ErrorDetails{Var: "LEVEL", Rule: "pattern", Value: "3"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			target := reflect.New(reflect.TypeOf(tc.target))
+			if !errors.As(err, target.Interface()) {
+				t.Fatalf("expected to find %T in \"%v\"", tc.target, err)
+			}
+
+			found := target.Elem().Interface().(internal.ValidationError)
+			if found.Code() != tc.code {
+				t.Errorf("expected code %s, got %s", tc.code, found.Code())
+			}
+			if found.Details() != tc.expected {
+				t.Errorf("expected: %+v\n Actual: %+v", tc.expected, found.Details())
+			}
+		})
+	}
+
+	t.Run("Location in message", func(t *testing.T) {
+		const expectedMessage = "var PORT is not a valid int (.env:2)"
+
+		var typeError *internal.TypeError
+		if !errors.As(err, &typeError) {
+			t.Fatalf("expected to find TypeError in \"%v\"", err)
+		}
+		if typeError.Error() != expectedMessage {
+			t.Errorf("expected \"%s\", got \"%s\"", expectedMessage, typeError.Error())
+		}
+	})
+}
@@ -0,0 +1,309 @@
+package envcheck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/netip"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+/*
+Env holds values of vars declared in the schema, already parsed to their types. It is returned by
+Load. Typed accessors panic if the var is not declared in the schema or has another type, so
+mistakes in var names are found right away instead of silently reading empty values.
+*/
+type Env struct {
+	config Config
+	vars   map[string]VarEntry
+	raw    map[string]string
+	values map[string]any
+	// Changes of os.env made by Load, nil for Env returned by Validate
+	journal *internal.EnvJournal
+}
+
+/*
+newEnv parses values of config vars returned by lookupEnv. Values which can not be parsed are
+kept only as they are set and their errors are returned together with Env.
+*/
+func newEnv(config Config, lookupEnv internal.LookupEnvFunc) (*Env, error) {
+	e := &Env{
+		config: config,
+		vars:   make(map[string]VarEntry, len(config.Vars)),
+		raw:    make(map[string]string),
+		values: make(map[string]any),
+	}
+
+	var parseError error = nil
+	for _, v := range config.Vars {
+		e.vars[v.Name] = v
+
+		value, isSet := lookupEnv(v.Name)
+		if !isSet {
+			continue
+		}
+
+		e.raw[v.Name] = value
+		parsed, err := internal.ParseValue(v, value)
+		if err != nil {
+			parseError = errors.Join(parseError, err)
+			continue
+		}
+		e.values[v.Name] = parsed
+	}
+
+	return e, parseError
+}
+
+/*
+Restore undoes changes which Load made in os.env: vars set by Load get the values they had before
+it, or are unset if they were not set. It is useful in tests and before reloading the
+configuration. Calling Restore again, or on Env returned by Validate, does nothing. Values held by
+Env are not changed.
+*/
+func (e *Env) Restore() error {
+	if e.journal == nil {
+		return nil
+	}
+	return e.journal.Rollback()
+}
+
+// Schema returns the schema which the values were validated against
+func (e *Env) Schema() Config {
+	return e.config
+}
+
+// Lookup returns the value of a declared var as it is set in the environment
+func (e *Env) Lookup(name string) (string, bool) {
+	e.entry(name)
+	value, isSet := e.raw[name]
+	return value, isSet
+}
+
+// IsSet tells whether a declared var is set
+func (e *Env) IsSet(name string) bool {
+	_, isSet := e.Lookup(name)
+	return isSet
+}
+
+// String returns value of a string or any var, "" if it is not set
+func (e *Env) String(name string) string {
+	return typedValue[string](e, name, StringType, AnyType)
+}
+
+// Int returns value of an int or port var, 0 if it is not set
+func (e *Env) Int(name string) int {
+	return typedValue[int](e, name, IntType, PortType)
+}
+
+// Float returns value of a float var, 0 if it is not set
+func (e *Env) Float(name string) float64 {
+	return typedValue[float64](e, name, FloatType)
+}
+
+// Bool returns value of a bool var, false if it is not set
+func (e *Env) Bool(name string) bool {
+	return typedValue[bool](e, name, BoolType)
+}
+
+// URL returns a copy of the value of a url var, nil if it is not set
+func (e *Env) URL(name string) *url.URL {
+	u := typedValue[*url.URL](e, name, URLType)
+	if u == nil {
+		return nil
+	}
+	clone := *u
+	if u.User != nil {
+		user := *u.User
+		clone.User = &user
+	}
+	return &clone
+}
+
+// HostPort returns value of a hostport var, zero HostPort if it is not set
+func (e *Env) HostPort(name string) HostPort {
+	return typedValue[HostPort](e, name, HostPortType)
+}
+
+// IP returns value of an ip var, zero netip.Addr if it is not set
+func (e *Env) IP(name string) netip.Addr {
+	return typedValue[netip.Addr](e, name, IPType)
+}
+
+// CIDR returns value of a cidr var, zero netip.Prefix if it is not set
+func (e *Env) CIDR(name string) netip.Prefix {
+	return typedValue[netip.Prefix](e, name, CIDRType)
+}
+
+// Duration returns value of a duration var, 0 if it is not set
+func (e *Env) Duration(name string) time.Duration {
+	return typedValue[time.Duration](e, name, DurationType)
+}
+
+// Time returns value of a time var, zero time.Time if it is not set
+func (e *Env) Time(name string) time.Time {
+	return typedValue[time.Time](e, name, TimeType)
+}
+
+// Location returns the location of a timezone var, nil if it is not set
+func (e *Env) Location(name string) *time.Location {
+	return typedValue[*time.Location](e, name, TimezoneType)
+}
+
+// Cron returns the schedule of a cron var, zero CronSchedule if it is not set
+func (e *Env) Cron(name string) CronSchedule {
+	return typedValue[CronSchedule](e, name, CronType)
+}
+
+// Size returns value of a size var in bytes, 0 if it is not set
+func (e *Env) Size(name string) int64 {
+	return typedValue[int64](e, name, SizeType)
+}
+
+// Quantity returns value of a quantity var in its declared unit, 0 if it is not set
+func (e *Env) Quantity(name string) float64 {
+	return typedValue[float64](e, name, QuantityType)
+}
+
+// List returns a copy of the items of a list var, nil if it is not set
+func (e *Env) List(name string) []any {
+	return slices.Clone(typedValue[[]any](e, name, ListType))
+}
+
+// Map returns a copy of the entries of a map var, nil if it is not set
+func (e *Env) Map(name string) map[string]any {
+	return maps.Clone(typedValue[map[string]any](e, name, MapType))
+}
+
+/*
+ListOf returns the items of a list var as T, which must be the type that the element type of the
+var parses to, like ListOf[int] for int items or ListOf[*url.URL] for url items.
+*/
+func ListOf[T any](e *Env, name string) []T {
+	items := e.List(name)
+	if items == nil {
+		return nil
+	}
+
+	list := make([]T, len(items))
+	for i, item := range items {
+		list[i] = itemValue[T](name, item)
+	}
+	return list
+}
+
+// MapOf returns the entries of a map var with values as T, see ListOf
+func MapOf[T any](e *Env, name string) map[string]T {
+	entries := e.Map(name)
+	if entries == nil {
+		return nil
+	}
+
+	m := make(map[string]T, len(entries))
+	for key, item := range entries {
+		m[key] = itemValue[T](name, item)
+	}
+	return m
+}
+
+func itemValue[T any](name string, item any) T {
+	value, ok := item.(T)
+	if !ok {
+		panic(fmt.Sprintf("envcheck: items of var %s are %T, not %T", name, item, value))
+	}
+	return value
+}
+
+/*
+JSON returns value of a json var decoded by encoding/json: map[string]any for objects, []any for
+arrays, string, float64, bool or nil. The value is decoded on every call, so it may be modified.
+nil is returned if the var is not set.
+*/
+func (e *Env) JSON(name string) any {
+	var value any
+	if raw, isSet := e.rawJSON(name); isSet {
+		_ = json.Unmarshal([]byte(raw), &value) // it was decoded by validation already
+	}
+	return value
+}
+
+// DecodeJSON decodes value of a json var to v with encoding/json, nothing is done if it is not set
+func (e *Env) DecodeJSON(name string, v any) error {
+	raw, isSet := e.rawJSON(name)
+	if !isSet {
+		return nil
+	}
+	return json.Unmarshal([]byte(raw), v)
+}
+
+func (e *Env) rawJSON(name string) (string, bool) {
+	if v := e.entry(name); v.Type != JSONType {
+		panic(fmt.Sprintf("envcheck: var %s has type %s, not json", name, v.Type))
+	}
+	raw, isSet := e.raw[name]
+	return raw, isSet
+}
+
+// Unmarshal fills struct pointed by v with the values, see the Unmarshal function
+func (e *Env) Unmarshal(v any) error {
+	return internal.Unmarshal(&e.config, func(name string) (string, bool) {
+		value, isSet := e.raw[name]
+		return value, isSet
+	}, v)
+}
+
+// ExportFormat is the output format of Env.Export
+type ExportFormat = internal.ExportFormat
+
+const (
+	ShellExport      = internal.ShellExport
+	FishExport       = internal.FishExport
+	PowerShellExport = internal.PowerShellExport
+	DockerExport     = internal.DockerExport
+	SystemdExport    = internal.SystemdExport
+	JSONExport       = internal.JSONExport
+	YAMLExport       = internal.YAMLExport
+	GitHubExport     = internal.GitHubExport
+	KubernetesExport = internal.KubernetesExport
+)
+
+// ExportOptions sets name and namespace of Kubernetes manifests rendered by Env.Export
+type ExportOptions = internal.ExportOptions
+
+/*
+Export renders the values of the vars which are set, in the order of the schema, in the format of
+another tool: shell export lines, env files, JSON, YAML, $GITHUB_ENV lines or Kubernetes
+manifests. Values are escaped as the format requires; an error is returned if one can not be
+represented in it. Secret vars are exported like the others, except in KubernetesExport where
+they go to a Secret instead of the ConfigMap.
+*/
+func (e *Env) Export(format ExportFormat, o ExportOptions) (string, error) {
+	return internal.RenderExport(&e.config, e.raw, format, o)
+}
+
+func (e *Env) entry(name string) VarEntry {
+	v, ok := e.vars[name]
+	if !ok {
+		panic(fmt.Sprintf("envcheck: var %s is not declared in schema", name))
+	}
+	return v
+}
+
+func typedValue[T any](e *Env, name string, types ...SupportedVarType) T {
+	v := e.entry(name)
+
+	for _, t := range types {
+		if v.Type == t {
+			value, _ := e.values[name].(T)
+			return value
+		}
+	}
+
+	var zero T
+	panic(fmt.Sprintf("envcheck: var %s has type %s, not %T", name, v.Type, zero))
+}
@@ -0,0 +1,428 @@
+package envcheck_test
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	envcheck "github.com/jogang0304/envcheck/pkg"
+)
+
+func expectPanic(t *testing.T, expectedMessage string, f func()) {
+	t.Helper()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatalf("expected panic \"%s\"", expectedMessage)
+		}
+		if message, _ := r.(string); !strings.Contains(message, expectedMessage) {
+			t.Fatalf("expected panic \"%s\", got \"%v\"", expectedMessage, r)
+		}
+	}()
+
+	f()
+}
+
+func TestEnv(t *testing.T) {
+	const configFileContent = `
+vars:
+  - name: PORT
+    type: int
+    default_value: 8080
+  - name: RATIO
+    type: float
+  - name: DEBUG
+    type: bool
+  - name: NAME
+    type: string
+  - name: EXTRA
+    type: any
+  - name: UNSET
+    type: int
+`
+	const envFileContent = `
+RATIO=0.25
+DEBUG=true
+NAME=service
+EXTRA={"a": 1}
+`
+	keys := []string{"PORT", "RATIO", "DEBUG", "NAME", "EXTRA", "UNSET"}
+	defer restoreEnv(saveAndClearEnv(keys))
+
+	createFilesInTempDir(t, configFileContent, envFileContent)
+
+	env, err := envcheck.Load()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	t.Run("Typed accessors", func(t *testing.T) {
+		if port := env.Int("PORT"); port != 8080 {
+			t.Errorf("expected PORT to be 8080, got %d", port)
+		}
+		if ratio := env.Float("RATIO"); ratio != 0.25 {
+			t.Errorf("expected RATIO to be 0.25, got %f", ratio)
+		}
+		if !env.Bool("DEBUG") {
+			t.Error("expected DEBUG to be true")
+		}
+		if name := env.String("NAME"); name != "service" {
+			t.Errorf("expected NAME to be service, got %s", name)
+		}
+		if extra := env.String("EXTRA"); extra != `{"a": 1}` {
+			t.Errorf("expected EXTRA to be {\"a\": 1}, got %s", extra)
+		}
+	})
+
+	t.Run("Unset var", func(t *testing.T) {
+		if env.IsSet("UNSET") {
+			t.Error("expected UNSET not to be set")
+		}
+		if value := env.Int("UNSET"); value != 0 {
+			t.Errorf("expected unset var to have zero value, got %d", value)
+		}
+	})
+
+	t.Run("Undeclared var", func(t *testing.T) {
+		expectPanic(t, "var MISSING is not declared in schema", func() {
+			env.String("MISSING")
+		})
+	})
+
+	t.Run("Wrong type", func(t *testing.T) {
+		expectPanic(t, "var NAME has type string, not int", func() {
+			env.Int("NAME")
+		})
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		var s struct {
+			Port int    `env:"PORT"`
+			Name string `env:"NAME"`
+		}
+		if err := env.Unmarshal(&s); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if s.Port != 8080 || s.Name != "service" {
+			t.Errorf("unexpected result %+v", s)
+		}
+	})
+}
+
+func TestEnvRestore(t *testing.T) {
+	const configFileContent = `
+vars:
+  - name: PORT
+    type: int
+    default_value: 8080
+  - name: NAME
+    type: string
+`
+	keys := []string{"PORT", "NAME", "EXTRA"}
+	defer restoreEnv(saveAndClearEnv(keys))
+
+	createFilesInTempDir(t, configFileContent, "NAME=service\nEXTRA=extra")
+	t.Setenv("NAME", "process")
+
+	env, err := envcheck.Load(envcheck.WithPrecedence(envcheck.FileWins))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	checkEnvVars(t, map[string]string{"PORT": "8080", "NAME": "service", "EXTRA": "extra"})
+
+	if err := env.Restore(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	checkEnvVars(t, map[string]string{"NAME": "process"})
+	for _, key := range []string{"PORT", "EXTRA"} {
+		if value, isSet := os.LookupEnv(key); isSet {
+			t.Errorf("environment variable %s must not be set, but it is %s", key, value)
+		}
+	}
+
+	if port := env.Int("PORT"); port != 8080 {
+		t.Errorf("expected Env to keep PORT 8080 after Restore, got %d", port)
+	}
+}
+
+func TestEnvExport(t *testing.T) {
+	schema := envcheck.Schema(
+		envcheck.Var("PORT").Int().Default(8080),
+		envcheck.Var("TOKEN").Str().Secret(),
+		envcheck.Var("NAME").Str(),
+	)
+
+	env, err := envcheck.Validate(schema, map[string]string{"TOKEN": "a'b", "OTHER": "x"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	export, err := env.Export(envcheck.ShellExport, envcheck.ExportOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if expected := "export PORT='8080'\nexport TOKEN='a'\\''b'\n"; export != expected {
+		t.Errorf("expected export \"%s\", got \"%s\"", expected, export)
+	}
+
+	manifests, err := env.Export(envcheck.KubernetesExport, envcheck.ExportOptions{Name: "api"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	configMap, secret, _ := strings.Cut(manifests, "---\n")
+	if !strings.Contains(configMap, `PORT: "8080"`) || strings.Contains(configMap, "TOKEN") {
+		t.Errorf("expected only PORT in ConfigMap, got\n%s", configMap)
+	}
+	if !strings.Contains(secret, "TOKEN: YSdi") {
+		t.Errorf("expected TOKEN in Secret, got\n%s", secret)
+	}
+}
+
+func TestEnvNetworkValues(t *testing.T) {
+	schema := envcheck.Schema(
+		envcheck.Var("API_URL").URL("https").RequireHost().Required(),
+		envcheck.Var("ADDR").HostPort().Default("localhost:8080"),
+		envcheck.Var("BIND_IP").IP().IPVersion(4),
+		envcheck.Var("SUBNET").CIDR(),
+		envcheck.Var("PORT").Port(),
+	)
+
+	env, err := envcheck.Validate(schema, map[string]string{
+		"API_URL": "https://api.example.com",
+		"BIND_IP": "0.0.0.0",
+		"SUBNET":  "10.0.0.0/8",
+		"PORT":    "443",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	u := env.URL("API_URL")
+	if u.Host != "api.example.com" {
+		t.Errorf("expected host api.example.com, got %s", u.Host)
+	}
+	u.Host = "changed"
+	if host := env.URL("API_URL").Host; host != "api.example.com" {
+		t.Errorf("expected URL to be copied, got host %s", host)
+	}
+	if addr := env.HostPort("ADDR"); addr.String() != "localhost:8080" {
+		t.Errorf("expected localhost:8080, got %s", addr)
+	}
+	if ip := env.IP("BIND_IP"); ip.String() != "0.0.0.0" {
+		t.Errorf("expected 0.0.0.0, got %s", ip)
+	}
+	if subnet := env.CIDR("SUBNET"); subnet.Bits() != 8 {
+		t.Errorf("expected prefix length 8, got %d", subnet.Bits())
+	}
+	if port := env.Int("PORT"); port != 443 {
+		t.Errorf("expected port 443, got %d", port)
+	}
+	expectPanic(t, "var PORT has type port, not *url.URL", func() { env.URL("PORT") })
+
+	_, err = envcheck.Validate(schema, map[string]string{
+		"API_URL": "http://api.example.com",
+		"PORT":    "99999",
+	})
+	for _, expected := range []string{
+		"var API_URL is not a valid url: scheme must be one of https",
+		"var PORT is not a valid port: must be a number from 1 to 65535",
+	} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain \"%s\", got \"%v\"", expected, err)
+		}
+	}
+}
+
+func TestEnvTimeValues(t *testing.T) {
+	schema := envcheck.Schema(
+		envcheck.Var("TIMEOUT").Duration().Min("1s").Max("1m").Default("30s"),
+		envcheck.Var("RELEASE").Time("2006-01-02"),
+		envcheck.Var("TZ").Timezone(),
+		envcheck.Var("SCHEDULE").Cron(),
+	)
+
+	env, err := envcheck.Validate(schema, map[string]string{
+		"RELEASE":  "2024-05-01",
+		"TZ":       "UTC",
+		"SCHEDULE": "0 3 * * *",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if timeout := env.Duration("TIMEOUT"); timeout != 30*time.Second {
+		t.Errorf("expected 30s, got %v", timeout)
+	}
+	if release := env.Time("RELEASE"); release.Month() != time.May || release.Day() != 1 {
+		t.Errorf("expected 2024-05-01, got %v", release)
+	}
+	if location := env.Location("TZ"); location != time.UTC {
+		t.Errorf("expected UTC, got %v", location)
+	}
+	if !env.Cron("SCHEDULE").Matches(time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC)) {
+		t.Error("expected schedule to match 03:00")
+	}
+
+	_, err = envcheck.Validate(schema, map[string]string{"TIMEOUT": "2m", "SCHEDULE": "0 25 * * *"})
+	for _, expected := range []string{
+		"var TIMEOUT is not a valid duration: must be at most 1m",
+		"var SCHEDULE is not a valid cron: hour field: 25 is out of range 0-23",
+	} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain \"%s\", got \"%v\"", expected, err)
+		}
+	}
+}
+
+func TestEnvQuantityValues(t *testing.T) {
+	schema := envcheck.Schema(
+		envcheck.Var("MEMORY_LIMIT").Size().Max("4GiB"),
+		envcheck.Var("RATE").Quantity("rps").Unit("rpm", 1.0/60).Min("1"),
+	)
+
+	env, err := envcheck.Validate(schema, map[string]string{
+		"MEMORY_LIMIT": "512MiB",
+		"RATE":         "300rpm",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if limit := env.Size("MEMORY_LIMIT"); limit != 512<<20 {
+		t.Errorf("expected 512MiB in bytes, got %d", limit)
+	}
+	if rate := env.Quantity("RATE"); rate != 5 {
+		t.Errorf("expected 5 rps, got %v", rate)
+	}
+	if raw, _ := env.Lookup("RATE"); raw != "300rpm" {
+		t.Errorf("expected raw value 300rpm, got %s", raw)
+	}
+
+	_, err = envcheck.Validate(schema, map[string]string{"MEMORY_LIMIT": "5GB", "RATE": "30rpm"})
+	for _, expected := range []string{
+		"var MEMORY_LIMIT is not a valid size: must be at most 4GiB",
+		"var RATE is not a valid quantity: must be at least 1rps",
+	} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain \"%s\", got \"%v\"", expected, err)
+		}
+	}
+}
+
+func TestEnvCollectionValues(t *testing.T) {
+	schema := envcheck.Schema(
+		envcheck.Var("PORTS").List().Of(envcheck.Item().Port()).Items(1, 3).Unique(),
+		envcheck.Var("LABELS").Map().Separator(";").KeyValueSeparator(":"),
+		envcheck.Var("LEVELS").Map().Of(envcheck.Item().AllowedValues("debug", "info")),
+	)
+
+	env, err := envcheck.Validate(schema, map[string]string{
+		"PORTS":  "80,443",
+		"LABELS": "team:core; tier:web",
+		"LEVELS": "db=info",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if ports := envcheck.ListOf[int](env, "PORTS"); !slices.Equal(ports, []int{80, 443}) {
+		t.Errorf("expected ports 80 and 443, got %v", ports)
+	}
+	labels := envcheck.MapOf[string](env, "LABELS")
+	if !maps.Equal(labels, map[string]string{"team": "core", "tier": "web"}) {
+		t.Errorf("unexpected labels %v", labels)
+	}
+	env.List("PORTS")[0] = 0
+	if port := env.List("PORTS")[0]; port != 80 {
+		t.Errorf("expected List to return a copy, got %v", port)
+	}
+	expectPanic(t, "items of var PORTS are int, not string", func() {
+		envcheck.ListOf[string](env, "PORTS")
+	})
+
+	_, err = envcheck.Validate(schema, map[string]string{
+		"PORTS":  "80,http,443,8080",
+		"LEVELS": "db=info,http=trace",
+	})
+	for _, expected := range []string{
+		"var PORTS is not a valid list: must have at most 3 items, got 4",
+		"var LEVELS[http] is not one of allowed values debug, info",
+	} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain \"%s\", got \"%v\"", expected, err)
+		}
+	}
+
+	_, err = envcheck.Validate(schema, map[string]string{"PORTS": "80,http"})
+	var typeErr *envcheck.TypeError
+	if !errors.As(err, &typeErr) || typeErr.Details().Element != "1" {
+		t.Errorf("expected TypeError of item 1, got \"%v\"", err)
+	}
+}
+
+func TestEnvJSONValues(t *testing.T) {
+	schema := envcheck.Schema(
+		envcheck.Var("FEATURE_CONFIG").JSON(&envcheck.JSONSchema{
+			Type:     "object",
+			Required: []string{"mode"},
+			Properties: map[string]envcheck.JSONSchema{
+				"mode":    {Enum: []any{"fast", "safe"}},
+				"retries": {Type: "integer"},
+			},
+		}),
+		envcheck.Var("EXTRA").JSON(nil),
+	)
+
+	env, err := envcheck.Validate(schema, map[string]string{
+		"FEATURE_CONFIG": `{"mode": "safe", "retries": 3}`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	config, ok := env.JSON("FEATURE_CONFIG").(map[string]any)
+	if !ok || config["mode"] != "safe" || config["retries"] != 3.0 {
+		t.Errorf("unexpected value %v", env.JSON("FEATURE_CONFIG"))
+	}
+	config["mode"] = "fast"
+	if mode := env.JSON("FEATURE_CONFIG").(map[string]any)["mode"]; mode != "safe" {
+		t.Errorf("expected JSON to return a copy, got mode %v", mode)
+	}
+
+	var decoded struct {
+		Mode    string `json:"mode"`
+		Retries int    `json:"retries"`
+	}
+	if err := env.DecodeJSON("FEATURE_CONFIG", &decoded); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if decoded.Mode != "safe" || decoded.Retries != 3 {
+		t.Errorf("unexpected decoded value %+v", decoded)
+	}
+	if extra := env.JSON("EXTRA"); extra != nil {
+		t.Errorf("expected nil for unset var, got %v", extra)
+	}
+	expectPanic(t, "var FEATURE_CONFIG has type json, not string", func() {
+		env.String("FEATURE_CONFIG")
+	})
+
+	_, err = envcheck.Validate(schema, map[string]string{
+		"FEATURE_CONFIG": `{"mode": "slow", "retries": 1.5}`,
+		"EXTRA":          `{`,
+	})
+	for _, expected := range []string{
+		`var FEATURE_CONFIG at /mode is not a valid json: must be one of "fast", "safe"`,
+		"var FEATURE_CONFIG at /retries is not a valid json: must be of type integer, got number",
+		"var EXTRA is not a valid json: must be valid JSON",
+	} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error to contain \"%s\", got \"%v\"", expected, err)
+		}
+	}
+}
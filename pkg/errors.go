@@ -0,0 +1,53 @@
+package envcheck
+
+import (
+	"github.com/jogang0304/envcheck/internal"
+)
+
+/*
+Problems found by Load and Validate are reported as errors of the types below, joined together.
+Each of them implements ValidationError, so they can be inspected with errors.As instead of
+matching error text:
+
+	var missing *envcheck.MissingError
+	if errors.As(err, &missing) {
+		log.Printf("%s: set %s", missing.Code(), missing.Var)
+	}
+
+	var problem envcheck.ValidationError
+	if errors.As(err, &problem) {
+		d := problem.Details() // var, rule, value, file and line
+	}
+
+Values of vars declared as secret are replaced with RedactedValue.
+*/
+type ValidationError = internal.ValidationError
+
+// ErrorDetails are fields common to all validation errors
+type ErrorDetails = internal.ErrorDetails
+
+type (
+	// Required var is not set and has no default value
+	MissingError = internal.MissingError
+	// Value can not be parsed as the declared type
+	TypeError = internal.TypeError
+	// Value does not match the pattern
+	PatternError = internal.PatternError
+	// Value is not one of the allowed values
+	AllowedValuesError = internal.AllowedValuesError
+	// Declaration of a var is invalid
+	SchemaError = internal.SchemaError
+	// All problems of one var, unless WithFailFast is set
+	VarError = internal.VarError
+)
+
+// Error codes returned by ValidationError.Code
+const (
+	CodeMissing = internal.CodeMissing
+	CodeType    = internal.CodeType
+	CodePattern = internal.CodePattern
+	CodeSchema  = internal.CodeSchema
+	CodeAllowed = internal.CodeAllowed
+)
+
+const RedactedValue = internal.RedactedValue
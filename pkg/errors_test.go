@@ -0,0 +1,58 @@
+package envcheck_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	envcheck "github.com/jogang0304/envcheck/pkg"
+)
+
+func TestValidationErrors(t *testing.T) {
+	defer restoreEnv(saveAndClearEnv([]string{"PORT", "API_KEY"}))
+
+	createFilesInTempDir(t, "", "PORT=http\nAPI_KEY=plain-text-key")
+
+	schema := envcheck.Schema(
+		envcheck.Var("PORT").Int(),
+		envcheck.Var("API_KEY").Pattern("^key_").Secret(),
+		envcheck.Var("HOST").Required(),
+	)
+
+	_, err := envcheck.Load(envcheck.WithSchema(schema))
+	if err == nil {
+		t.Fatal("expected to get an error")
+	}
+
+	var typeError *envcheck.TypeError
+	if !errors.As(err, &typeError) {
+		t.Fatalf("expected to find TypeError in \"%v\"", err)
+	}
+	if typeError.Code() != envcheck.CodeType || typeError.Var != "PORT" ||
+		typeError.Value != "http" || filepath.Base(typeError.File) != ".env" ||
+		typeError.Line != 1 {
+		t.Errorf("unexpected TypeError %s %+v", typeError.Code(), typeError.Details())
+	}
+
+	var patternError *envcheck.PatternError
+	if !errors.As(err, &patternError) {
+		t.Fatalf("expected to find PatternError in \"%v\"", err)
+	}
+	if patternError.Value != envcheck.RedactedValue || patternError.Line != 2 {
+		t.Errorf("unexpected PatternError %+v", patternError.Details())
+	}
+	if strings.Contains(err.Error(), "plain-text-key") {
+		t.Errorf("value of secret var is shown in \"%v\"", err)
+	}
+
+	var missingError *envcheck.MissingError
+	if !errors.As(err, &missingError) || missingError.Code() != envcheck.CodeMissing {
+		t.Fatalf("expected to find MissingError in \"%v\"", err)
+	}
+
+	var varError *envcheck.VarError
+	if !errors.As(err, &varError) {
+		t.Fatalf("expected to find VarError in \"%v\"", err)
+	}
+}
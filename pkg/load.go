@@ -2,40 +2,142 @@ package envcheck
 
 import (
 	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
 
 	"github.com/jogang0304/envcheck/internal"
 )
 
-func Load() error {
-	err := internal.LoadDotenv()
+/*
+This function loads .env files into os.env and validates variables against .env.yaml.
+Both are looked up in the project root: the nearest directory above the working directory (or
+the one set by WithStartDir) which holds .env.yaml, go.mod or .git.
+By default variables already set in the process are not overwritten, see WithPrecedence.
+Files are loaded and validated on a snapshot of the environment, see Validate. os.env is modified
+only if validation succeeds, and if setting any var fails, vars set so far are restored.
+The returned Env holds values of the declared vars parsed to their types. Its Restore method
+undoes changes which Load made in os.env.
+*/
+func Load(opts ...Option) (*Env, error) {
+	o := newOptions(opts)
+
+	snapshot, err := initialEnv(o)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to read initial environment"), err)
+	}
+
+	root, err := projectRoot(o)
 	if err != nil {
-		return errors.Join(errors.New("failed to load .env"), err)
+		return nil, errors.Join(errors.New("failed to find project root"), err)
 	}
+	readFile := fileReader(o)
 
-	config, err := internal.GetConfig()
+	dotenvOptions := internal.DotenvOptions{
+		Dir:        root,
+		Selector:   o.envSelector,
+		Env:        snapshot,
+		Precedence: o.precedence,
+		ReadFile:   readFile,
+		Sources:    make(map[string]internal.VarSource),
+	}
+	for _, f := range o.envFiles {
+		dotenvOptions.Files = append(
+			dotenvOptions.Files,
+			internal.DotenvFile{Path: resolvePath(root, f)},
+		)
+	}
+
+	shadowed, err := internal.LoadDotenv(dotenvOptions)
+	if o.onShadowed != nil {
+		for _, s := range shadowed {
+			o.onShadowed(s)
+		}
+	}
 	if err != nil {
-		return errors.Join(errors.New("failed to get config"), err)
+		return nil, errors.Join(errors.New("failed to load .env"), err)
 	}
 
-	err = internal.PopulateUnsetVarsWithDefaults(&config)
+	config, err := loadSchema(o, root, readFile)
 	if err != nil {
-		return errors.Join(errors.New("failed to populate unset vars with defaults"), err)
+		return nil, errors.Join(errors.New("failed to get config"), err)
 	}
 
-	err = internal.ValidateRequired(&config)
+	resolved, err := internal.Validate(&config, snapshot, internal.ValidateOptions{
+		FailFast: o.failFast,
+		Sources:  dotenvOptions.Sources,
+	})
 	if err != nil {
-		return errors.Join(errors.New("failed to validate required vars"), err)
+		return nil, err
 	}
 
-	err = internal.ValidateTypes(&config)
+	env, err := newEnv(config, internal.LookupInMap(resolved))
 	if err != nil {
-		return errors.Join(errors.New("failed to validate var types"), err)
+		return nil, err
 	}
 
-	err = internal.ValidatePatterns(&config)
+	journal, err := internal.SetProcessEnv(resolved)
 	if err != nil {
-		return errors.Join(errors.New("failed to validate var patterns"), err)
+		return nil, errors.Join(errors.New("failed to set vars in os.env"), err)
+	}
+	env.journal = journal
+
+	return env, nil
+}
+
+// initialEnv returns snapshot of the environment which .env files are loaded on
+func initialEnv(o *options) (map[string]string, error) {
+	if o.environ != nil {
+		return internal.ParseEnviron(o.environ)
+	}
+	return internal.ParseEnviron(os.Environ())
+}
+
+func loadSchema(o *options, root string, readFile internal.ReadFileFunc) (Config, error) {
+	if o.schema == nil {
+		return internal.ReadConfig(readFile, resolvePath(root, o.schemaFile))
+	}
+	return checkSchema(*o.schema)
+}
+
+// checkSchema checks a schema which was not read from a file
+func checkSchema(config Config) (Config, error) {
+	if err := internal.CheckSchema(&config); err != nil {
+		return Config{}, errors.Join(errors.New("failed to check schema"), err)
+	}
+	return config, nil
+}
+
+// projectRoot returns directory against which relative paths are resolved
+func projectRoot(o *options) (string, error) {
+	if o.fsys != nil {
+		return ".", nil
+	}
+
+	start := o.startDir
+	if start == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", errors.Join(errors.New("failed to get current working directory"), err)
+		}
+		start = cwd
 	}
 
-	return nil
+	return internal.FindProjectRoot(start)
+}
+
+func fileReader(o *options) internal.ReadFileFunc {
+	if o.fsys == nil {
+		return os.ReadFile
+	}
+	return func(name string) ([]byte, error) {
+		return fs.ReadFile(o.fsys, filepath.ToSlash(name))
+	}
+}
+
+func resolvePath(root, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(root, path)
 }
@@ -2,8 +2,10 @@ package envcheck_test
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	envcheck "github.com/jogang0304/envcheck/pkg"
 )
@@ -84,7 +86,7 @@ func testLoadWithoutErrors(
 
 	createFilesInTempDir(t, configFileContent, envFileContent)
 
-	err := envcheck.Load()
+	_, err := envcheck.Load()
 	if err != nil {
 		t.Fatalf("unexpected error %v", err)
 	}
@@ -102,7 +104,7 @@ func testLoadWithErrors(
 
 	createFilesInTempDir(t, configFileContent, envFileContent)
 
-	err := envcheck.Load()
+	_, err := envcheck.Load()
 	if err == nil {
 		t.Fatalf("err == nil, but expected to fail with error \"%v\"", expectedError)
 	}
@@ -174,7 +176,7 @@ vars:
 			const envFileContent = `
 firstVar=test
 `
-			const expectedError = "failed to validate required vars"
+			const expectedError = "required var secondVar is not set"
 
 			varsToClean := []string{
 				"firstVar", "secondVar",
@@ -194,7 +196,7 @@ vars:
 		const envFileContent = `
 firstVar=test
 `
-		const expectedError = "failed to validate var types"
+		const expectedError = "var firstVar is not a valid int"
 
 		varsToClean := []string{
 			"firstVar",
@@ -214,7 +216,7 @@ vars:
 		const envFileContent = `
 firstVar=123
 `
-		const expectedError = "failed to validate var patterns"
+		const expectedError = "variable firstVar does not match pattern"
 
 		varsToClean := []string{
 			"firstVar",
@@ -241,4 +243,212 @@ firstVar=123
 
 		testLoadWithErrors(t, configFileContent, envFileContent, expectedError, varsToClean)
 	})
+
+	t.Run("Var is set in the process and in .env", func(t *testing.T) {
+		const configFileContent = `
+vars:
+  - name: firstVar
+    type: int
+`
+		const envFileContent = `
+firstVar=1
+`
+
+		testCases := []struct {
+			name          string
+			opts          []envcheck.Option
+			expectedValue string
+			expectedError string
+		}{
+			{name: "Process env wins by default", expectedValue: "2"},
+			{
+				name:          "File wins",
+				opts:          []envcheck.Option{envcheck.WithPrecedence(envcheck.FileWins)},
+				expectedValue: "1",
+			},
+			{
+				name:          "Error on conflict",
+				opts:          []envcheck.Option{envcheck.WithPrecedence(envcheck.ErrorOnConflict)},
+				expectedValue: "2",
+				expectedError: "var firstVar is already set in the process environment",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				originalEnv := saveAndClearEnv([]string{"firstVar"})
+				defer restoreEnv(originalEnv)
+
+				createFilesInTempDir(t, configFileContent, envFileContent)
+				t.Setenv("firstVar", "2")
+
+				var shadowed []envcheck.Shadowed
+				opts := append(tc.opts, envcheck.OnShadowed(func(s envcheck.Shadowed) {
+					shadowed = append(shadowed, s)
+				}))
+
+				_, err := envcheck.Load(opts...)
+				if tc.expectedError == "" && err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if tc.expectedError != "" &&
+					(err == nil || !strings.Contains(err.Error(), tc.expectedError)) {
+					t.Fatalf("expected \"%v\"\nto contain \"%v\"", err, tc.expectedError)
+				}
+
+				checkEnvVars(t, map[string]string{"firstVar": tc.expectedValue})
+
+				if len(shadowed) != 1 || shadowed[0].Key != "firstVar" ||
+					shadowed[0].ProcessValue != "2" || shadowed[0].FileValue != "1" {
+					t.Errorf("expected firstVar to be reported as shadowed, got %v", shadowed)
+				}
+			})
+		}
+	})
+
+	t.Run("Environment specific files", func(t *testing.T) {
+		const configFileContent = `
+vars:
+  - name: firstVar
+    type: string
+`
+		originalEnv := saveAndClearEnv([]string{"firstVar", "STAGE"})
+		defer restoreEnv(originalEnv)
+
+		createFilesInTempDir(t, configFileContent, "firstVar=base\n")
+		err := os.WriteFile(".env.test", []byte("firstVar=test\n"), 0o644)
+		if err != nil {
+			t.Fatalf("failed to create .env.test file: %v", err)
+		}
+		t.Setenv("STAGE", "test")
+
+		_, err = envcheck.Load(envcheck.WithEnvSelector("STAGE"))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		checkEnvVars(t, map[string]string{"firstVar": "test"})
+	})
+
+	t.Run("Files are looked up in the project root", func(t *testing.T) {
+		const configFileContent = `
+vars:
+  - name: firstVar
+    required: true
+    type: int
+`
+		originalEnv := saveAndClearEnv([]string{"firstVar"})
+		defer restoreEnv(originalEnv)
+
+		createFilesInTempDir(t, configFileContent, "firstVar=1\n")
+		root, err := os.Getwd()
+		if err != nil {
+			t.Fatalf("failed to get working directory: %v", err)
+		}
+
+		subdir := filepath.Join(root, "internal", "server")
+		if err := os.MkdirAll(subdir, 0o755); err != nil {
+			t.Fatalf("failed to create %s: %v", subdir, err)
+		}
+
+		t.Run("From working directory", func(t *testing.T) {
+			if err := os.Chdir(subdir); err != nil {
+				t.Fatalf("failed to chdir to %s", subdir)
+			}
+			defer restoreEnv(saveAndClearEnv([]string{"firstVar"}))
+
+			if _, err := envcheck.Load(); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			checkEnvVars(t, map[string]string{"firstVar": "1"})
+		})
+
+		t.Run("From start dir", func(t *testing.T) {
+			if err := os.Chdir(t.TempDir()); err != nil {
+				t.Fatal("failed to chdir to temp dir")
+			}
+			defer restoreEnv(saveAndClearEnv([]string{"firstVar"}))
+
+			if _, err := envcheck.Load(envcheck.WithStartDir(subdir)); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			checkEnvVars(t, map[string]string{"firstVar": "1"})
+		})
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		const configFileContent = `
+vars:
+  - name: firstVar
+    required: true
+    type: int
+  - name: secondVar
+    required: true
+    type: string
+`
+		keys := []string{"firstVar", "secondVar"}
+
+		t.Run("Schema file and env files", func(t *testing.T) {
+			defer restoreEnv(saveAndClearEnv(keys))
+
+			createFilesInTempDir(t, "vars: []", "")
+			files := map[string]string{
+				"config/schema.yaml": configFileContent,
+				"config/base.env":    "firstVar=1\nsecondVar=base",
+				"config/prod.env":    "secondVar=prod",
+			}
+			if err := os.Mkdir("config", 0o755); err != nil {
+				t.Fatalf("failed to create config dir: %v", err)
+			}
+			for name, content := range files {
+				if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+					t.Fatalf("failed to create %s file: %v", name, err)
+				}
+			}
+
+			_, err := envcheck.Load(
+				envcheck.WithSchemaFile("config/schema.yaml"),
+				envcheck.WithEnvFiles("config/base.env", "config/prod.env"),
+			)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			checkEnvVars(t, map[string]string{"firstVar": "1", "secondVar": "prod"})
+		})
+
+		t.Run("FS", func(t *testing.T) {
+			defer restoreEnv(saveAndClearEnv(keys))
+
+			if err := os.Chdir(t.TempDir()); err != nil {
+				t.Fatal("failed to chdir to temp dir")
+			}
+
+			fsys := fstest.MapFS{
+				".env.yaml": {Data: []byte(configFileContent)},
+				".env":      {Data: []byte("firstVar=1\nsecondVar=embedded")},
+			}
+
+			if _, err := envcheck.Load(envcheck.WithFS(fsys)); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			checkEnvVars(t, map[string]string{"firstVar": "1", "secondVar": "embedded"})
+		})
+
+		t.Run("Environ", func(t *testing.T) {
+			defer restoreEnv(saveAndClearEnv(keys))
+
+			createFilesInTempDir(t, configFileContent, "firstVar=1\nsecondVar=file")
+
+			_, err := envcheck.Load(envcheck.WithEnviron([]string{"secondVar=environ"}))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			checkEnvVars(t, map[string]string{"firstVar": "1", "secondVar": "environ"})
+		})
+	})
 }
@@ -0,0 +1,151 @@
+package envcheck
+
+import (
+	"io/fs"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+// DefaultSchemaFile is the name of the schema file in the project root
+const DefaultSchemaFile = ".env.yaml"
+
+// Precedence decides which value is used when a var is set both in the process and in a .env file
+type Precedence = internal.Precedence
+
+const (
+	// Value already set in the process environment is kept. This is the default.
+	ProcessEnvWins = internal.ProcessEnvWins
+	// Value from .env file overwrites the process environment
+	FileWins = internal.FileWins
+	// Var set in both places with different values makes Load fail
+	ErrorOnConflict = internal.ErrorOnConflict
+)
+
+// Shadowed describes a var which is set both in the process and in a .env file
+type Shadowed = internal.ShadowedVar
+
+// Option configures Load, Validate and Unmarshal. Options which do not concern them are ignored.
+type Option func(*options)
+
+type options struct {
+	precedence  Precedence
+	onShadowed  func(Shadowed)
+	envSelector string
+	startDir    string
+	schemaFile  string
+	envFiles    []string
+	fsys        fs.FS
+	environ     []string
+	schema      *Config
+	failFast    bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		precedence:  ProcessEnvWins,
+		envSelector: internal.DefaultEnvSelector,
+		schemaFile:  DefaultSchemaFile,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithPrecedence sets which value wins when a var is set both in the process and in a .env file
+func WithPrecedence(p Precedence) Option {
+	return func(o *options) {
+		o.precedence = p
+	}
+}
+
+/*
+OnShadowed registers a function which is called for every var set both in the process and in a
+.env file with different values.
+*/
+func OnShadowed(f func(Shadowed)) Option {
+	return func(o *options) {
+		o.onShadowed = f
+	}
+}
+
+/*
+WithEnvSelector sets the name of var which selects the environment, "APP_ENV" by default. If it is
+set to "staging", Load reads .env, .env.local, .env.staging and .env.staging.local, later files
+override earlier ones. An empty name disables loading of per-environment files.
+*/
+func WithEnvSelector(name string) Option {
+	return func(o *options) {
+		o.envSelector = name
+	}
+}
+
+/*
+WithStartDir sets the directory from which Load starts looking for the project root. The working
+directory is used by default.
+*/
+func WithStartDir(dir string) Option {
+	return func(o *options) {
+		o.startDir = dir
+	}
+}
+
+/*
+WithSchemaFile sets path of the schema file, .env.yaml by default. Relative paths are resolved
+against the project root.
+*/
+func WithSchemaFile(path string) Option {
+	return func(o *options) {
+		o.schemaFile = path
+	}
+}
+
+/*
+WithEnvFiles sets .env files to load instead of the cascade. All of them must exist, later files
+override earlier ones. Relative paths are resolved against the project root.
+*/
+func WithEnvFiles(paths ...string) Option {
+	return func(o *options) {
+		o.envFiles = paths
+	}
+}
+
+/*
+WithFS makes Load read the schema and .env files from fsys, for example from embed.FS. Paths are
+relative to the root of fsys and the project root is not looked up.
+*/
+func WithFS(fsys fs.FS) Option {
+	return func(o *options) {
+		o.fsys = fsys
+	}
+}
+
+/*
+WithEnviron sets the initial environment in the form of os.Environ(), "KEY=value" strings, which
+is used instead of os.Environ(). Its vars take part in precedence and validation as if they were
+exported by the parent process. If Load succeeds, they are set in os.env together with vars from
+.env files.
+*/
+func WithEnviron(environ []string) Option {
+	return func(o *options) {
+		o.environ = environ
+	}
+}
+
+// WithSchema makes Load validate against schema instead of reading the schema file
+func WithSchema(schema Config) Option {
+	return func(o *options) {
+		o.schema = &schema
+	}
+}
+
+/*
+WithFailFast makes validation stop at the first failing stage: required vars, types, patterns,
+allowed values.
+By default all checks are run and every problem is reported at once, grouped per var.
+*/
+func WithFailFast() Option {
+	return func(o *options) {
+		o.failFast = true
+	}
+}
@@ -0,0 +1,40 @@
+package envcheck
+
+import (
+	"errors"
+	"io"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+// Entry is a single "KEY=value" pair of a dotenv file
+type Entry = internal.DotenvEntry
+
+// QuoteStyle tells how the value of an Entry is quoted in the file
+type QuoteStyle = internal.QuoteStyle
+
+const (
+	Unquoted     = internal.Unquoted
+	SingleQuoted = internal.SingleQuoted
+	DoubleQuoted = internal.DoubleQuoted
+)
+
+// ParseError points to the line and column of a dotenv file where parsing failed
+type ParseError = internal.ParseError
+
+/*
+This function parses dotenv file from r and returns its entries in the order they appear in the
+file. It does not touch the process environment and does not expand references to other
+variables, so it is suitable for linting, diffing and editing tools.
+
+If some lines are invalid, entries from valid lines are returned together with the error. Every
+*ParseError can be reached with errors.As.
+*/
+func Parse(r io.Reader) ([]Entry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to read dotenv content"), err)
+	}
+
+	return internal.ParseDotenv("", string(data))
+}
@@ -0,0 +1,84 @@
+package envcheck_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	envcheck "github.com/jogang0304/envcheck/pkg"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Valid content", func(t *testing.T) {
+		const content = `# Connection settings
+export DB_HOST=localhost # primary
+DB_URL="postgres://${DB_HOST}/db\tx"
+
+DB_PASSWORD='$ecret'
+`
+		expectedEntries := []envcheck.Entry{
+			{
+				Key:           "DB_HOST",
+				RawValue:      "localhost",
+				Value:         "localhost",
+				Quote:         envcheck.Unquoted,
+				Line:          2,
+				Comments:      []string{"Connection settings"},
+				InlineComment: "primary",
+			},
+			{
+				Key:      "DB_URL",
+				RawValue: `postgres://${DB_HOST}/db\tx`,
+				Value:    "postgres://${DB_HOST}/db\tx",
+				Quote:    envcheck.DoubleQuoted,
+				Line:     3,
+			},
+			{
+				Key:      "DB_PASSWORD",
+				RawValue: "$ecret",
+				Value:    "$ecret",
+				Quote:    envcheck.SingleQuoted,
+				Line:     5,
+			},
+		}
+
+		originalEnv := saveAndClearEnv([]string{"DB_HOST", "DB_URL", "DB_PASSWORD"})
+		defer restoreEnv(originalEnv)
+
+		entries, err := envcheck.Parse(strings.NewReader(content))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if !reflect.DeepEqual(entries, expectedEntries) {
+			t.Errorf("expected entries: %v\n Actual entries: %v", expectedEntries, entries)
+		}
+
+		for _, entry := range entries {
+			if _, isSet := os.LookupEnv(entry.Key); isSet {
+				t.Errorf("Parse must not set environment variable %s", entry.Key)
+			}
+		}
+	})
+
+	t.Run("Invalid content", func(t *testing.T) {
+		entries, err := envcheck.Parse(strings.NewReader("VALID=1\nINVALID\n"))
+		if err == nil {
+			t.Fatal("expected to get an error")
+		}
+
+		var parseError *envcheck.ParseError
+		if !errors.As(err, &parseError) {
+			t.Fatalf("expected ParseError, got \"%v\"", err)
+		}
+		if parseError.Line != 2 {
+			t.Errorf("expected error on line 2, got line %d", parseError.Line)
+		}
+
+		if len(entries) != 1 || entries[0].Key != "VALID" {
+			t.Errorf("expected entries from valid lines to be returned, got %v", entries)
+		}
+	})
+}
@@ -0,0 +1,312 @@
+package envcheck
+
+import "github.com/jogang0304/envcheck/internal"
+
+// Config is the schema of environment variables, the content of .env.yaml
+type Config = internal.Config
+
+// VarEntry describes a single environment variable of Config
+type VarEntry = internal.VarEntry
+
+// SupportedVarType is the type of VarEntry
+type SupportedVarType = internal.SupportedVarType
+
+const (
+	StringType = internal.StringType
+	IntType    = internal.IntType
+	FloatType  = internal.FloatType
+	BoolType   = internal.BoolType
+	AnyType    = internal.AnyType
+
+	URLType      = internal.URLType
+	HostPortType = internal.HostPortType
+	IPType       = internal.IPType
+	CIDRType     = internal.CIDRType
+	PortType     = internal.PortType
+
+	DurationType = internal.DurationType
+	TimeType     = internal.TimeType
+	TimezoneType = internal.TimezoneType
+	CronType     = internal.CronType
+
+	SizeType     = internal.SizeType
+	QuantityType = internal.QuantityType
+
+	ListType = internal.ListType
+	MapType  = internal.MapType
+
+	JSONType = internal.JSONType
+)
+
+// HostPort is the value of a hostport var
+type HostPort = internal.HostPort
+
+// CronSchedule is the value of a cron var
+type CronSchedule = internal.CronSchedule
+
+// JSONSchema is the subset of JSON Schema which the value of a json var may be checked against
+type JSONSchema = internal.JSONSchema
+
+/*
+VarBuilder builds VarEntry step by step:
+
+	envcheck.Var("PORT").Int().Required().Default(8080)
+*/
+type VarBuilder struct {
+	entry VarEntry
+}
+
+// Var starts building a var with the given name. Its type is string until another one is set.
+func Var(name string) *VarBuilder {
+	return &VarBuilder{entry: VarEntry{Name: name, Type: StringType}}
+}
+
+// Type sets type of the var
+func (b *VarBuilder) Type(t SupportedVarType) *VarBuilder {
+	b.entry.Type = t
+	return b
+}
+
+// Str makes the var a string, it is not named String so VarBuilder is not a fmt.Stringer
+func (b *VarBuilder) Str() *VarBuilder { return b.Type(StringType) }
+
+func (b *VarBuilder) Int() *VarBuilder { return b.Type(IntType) }
+
+func (b *VarBuilder) Float() *VarBuilder { return b.Type(FloatType) }
+
+func (b *VarBuilder) Bool() *VarBuilder { return b.Type(BoolType) }
+
+func (b *VarBuilder) Any() *VarBuilder { return b.Type(AnyType) }
+
+// URL makes the var an absolute URL, which must have one of schemes if they are given
+func (b *VarBuilder) URL(schemes ...string) *VarBuilder {
+	b.entry.Schemes = schemes
+	return b.Type(URLType)
+}
+
+func (b *VarBuilder) HostPort() *VarBuilder { return b.Type(HostPortType) }
+
+func (b *VarBuilder) IP() *VarBuilder { return b.Type(IPType) }
+
+func (b *VarBuilder) CIDR() *VarBuilder { return b.Type(CIDRType) }
+
+func (b *VarBuilder) Port() *VarBuilder { return b.Type(PortType) }
+
+// RequireHost makes a url var fail validation if it has no host
+func (b *VarBuilder) RequireHost() *VarBuilder {
+	b.entry.RequireHost = true
+	return b
+}
+
+// IPVersion restricts an ip or cidr var to IPv4 or IPv6, version is 4 or 6
+func (b *VarBuilder) IPVersion(version int) *VarBuilder {
+	b.entry.IPVersion = version
+	return b
+}
+
+func (b *VarBuilder) Duration() *VarBuilder { return b.Type(DurationType) }
+
+// Time makes the var a point in time in layout of time.Parse, RFC 3339 if layout is ""
+func (b *VarBuilder) Time(layout string) *VarBuilder {
+	b.entry.Layout = layout
+	return b.Type(TimeType)
+}
+
+func (b *VarBuilder) Timezone() *VarBuilder { return b.Type(TimezoneType) }
+
+func (b *VarBuilder) Cron() *VarBuilder { return b.Type(CronType) }
+
+func (b *VarBuilder) Size() *VarBuilder { return b.Type(SizeType) }
+
+// Quantity makes the var a number with unit, which its values are normalized to
+func (b *VarBuilder) Quantity(unit string) *VarBuilder {
+	b.entry.Unit = unit
+	return b.Type(QuantityType)
+}
+
+// Unit adds another unit of a quantity var, factor is how many of the main unit it is
+func (b *VarBuilder) Unit(name string, factor float64) *VarBuilder {
+	if b.entry.Units == nil {
+		b.entry.Units = make(map[string]float64)
+	}
+	b.entry.Units[name] = factor
+	return b
+}
+
+func (b *VarBuilder) List() *VarBuilder { return b.Type(ListType) }
+
+func (b *VarBuilder) Map() *VarBuilder { return b.Type(MapType) }
+
+/*
+Item starts building the element of a list or map var, which has no name:
+
+	envcheck.Var("ORIGINS").List().Of(envcheck.Item().URL("https")).Unique()
+*/
+func Item() *VarBuilder {
+	return &VarBuilder{entry: VarEntry{Type: StringType}}
+}
+
+// Of sets type, pattern, allowed values and options of items of a list var or values of a map var
+func (b *VarBuilder) Of(item *VarBuilder) *VarBuilder {
+	element := item.Entry()
+	b.entry.Element = &element
+	return b
+}
+
+// Separator sets separator of list items or map entries, "," by default
+func (b *VarBuilder) Separator(separator string) *VarBuilder {
+	b.entry.Separator = separator
+	return b
+}
+
+// KeyValueSeparator sets separator of keys and values in map entries, "=" by default
+func (b *VarBuilder) KeyValueSeparator(separator string) *VarBuilder {
+	b.entry.KeyValueSeparator = separator
+	return b
+}
+
+// Items limits number of list items or map entries, most 0 means no upper limit
+func (b *VarBuilder) Items(least, most int) *VarBuilder {
+	b.entry.MinItems = least
+	b.entry.MaxItems = most
+	return b
+}
+
+// Unique makes a list var fail validation if its items repeat
+func (b *VarBuilder) Unique() *VarBuilder {
+	b.entry.Unique = true
+	return b
+}
+
+// JSON makes the var a JSON document, which must match schema if it is not nil
+func (b *VarBuilder) JSON(schema *JSONSchema) *VarBuilder {
+	b.entry.JSONSchema = schema
+	return b.Type(JSONType)
+}
+
+// Min sets the lower bound of a duration, size or quantity var, like "1s", "1MiB" or "0"
+func (b *VarBuilder) Min(value string) *VarBuilder {
+	b.entry.Min = value
+	return b
+}
+
+// Max sets the upper bound of a duration, size or quantity var, like "1m", "1GiB" or "100"
+func (b *VarBuilder) Max(value string) *VarBuilder {
+	b.entry.Max = value
+	return b
+}
+
+// Required marks the var as required
+func (b *VarBuilder) Required() *VarBuilder {
+	b.entry.Required = true
+	return b
+}
+
+// Default sets value which is used when the var is not set
+func (b *VarBuilder) Default(value any) *VarBuilder {
+	b.entry.DefaultValue = value
+	return b
+}
+
+// Pattern sets regex which value of the var must match
+func (b *VarBuilder) Pattern(pattern string) *VarBuilder {
+	b.entry.Pattern = &pattern
+	return b
+}
+
+// Secret marks the var as secret, so its value is redacted in errors
+func (b *VarBuilder) Secret() *VarBuilder {
+	b.entry.Secret = true
+	return b
+}
+
+// Description sets human readable description of the var
+func (b *VarBuilder) Description(text string) *VarBuilder {
+	b.entry.Description = text
+	return b
+}
+
+// AllowedValues sets values which the var may take
+func (b *VarBuilder) AllowedValues(values ...string) *VarBuilder {
+	b.entry.AllowedValues = values
+	return b
+}
+
+// Group sets section of the documentation which the var belongs to
+func (b *VarBuilder) Group(name string) *VarBuilder {
+	b.entry.Group = name
+	return b
+}
+
+// Entry returns the built VarEntry
+func (b *VarBuilder) Entry() VarEntry {
+	return b.entry
+}
+
+// Schema builds Config from vars
+func Schema(vars ...*VarBuilder) Config {
+	config := Config{Vars: make([]VarEntry, 0, len(vars))}
+	for _, v := range vars {
+		config.Vars = append(config.Vars, v.Entry())
+	}
+	return config
+}
+
+/*
+MergeConfigs merges configs into one. If several configs declare a var with the same name, the
+declaration from the later config is used, at the position of the first declaration.
+*/
+func MergeConfigs(configs ...Config) Config {
+	var merged Config
+	index := make(map[string]int)
+
+	for _, config := range configs {
+		for _, v := range config.Vars {
+			if i, ok := index[v.Name]; ok {
+				merged.Vars[i] = v
+				continue
+			}
+			index[v.Name] = len(merged.Vars)
+			merged.Vars = append(merged.Vars, v)
+		}
+	}
+
+	return merged
+}
+
+/*
+InferSchema drafts a schema from entries of .env files read with Parse, one slice per file.
+Types are inferred with the same parsers which validation uses, vars present in every file are
+required, URL, UUID and email values get a pattern and comments become descriptions. The draft
+is meant to be reviewed before use.
+*/
+func InferSchema(files ...[]Entry) Config {
+	return internal.InferSchema(files)
+}
+
+/*
+RenderExample renders .env.example content for schema: descriptions and rules as comments,
+default values, empty values for required vars and placeholders for required secrets. Optional
+vars without defaults are commented out and defaults of secret vars are never shown.
+*/
+func RenderExample(schema Config) string {
+	return internal.RenderExample(&schema)
+}
+
+// DocsFormat is the output format of RenderDocs
+type DocsFormat = internal.DocsFormat
+
+const (
+	MarkdownDocs = internal.MarkdownDocs
+	HTMLDocs     = internal.HTMLDocs
+)
+
+/*
+RenderDocs renders documentation of the vars declared in schema as a Markdown page or a
+standalone HTML page with the given title: name, type, required, default, pattern, allowed values
+and description of every var, in sections by VarEntry.Group. Default values of secret vars are
+never printed.
+*/
+func RenderDocs(schema Config, format DocsFormat, title string) (string, error) {
+	return internal.RenderDocs(&schema, format, title)
+}
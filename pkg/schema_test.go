@@ -0,0 +1,102 @@
+package envcheck_test
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	envcheck "github.com/jogang0304/envcheck/pkg"
+)
+
+func StringPtr(s string) *string {
+	return &s
+}
+
+func TestSchema(t *testing.T) {
+	t.Run("Builder", func(t *testing.T) {
+		schema := envcheck.Schema(
+			envcheck.Var("PORT").Int().Required().Default(8080),
+			envcheck.Var("RATIO").Float(),
+			envcheck.Var("DEBUG").Bool().Default(false),
+			envcheck.Var("NAME").Pattern("^[a-z]+$"),
+			envcheck.Var("EXTRA").Any(),
+			envcheck.Var("LEVEL").Int().Str(),
+		)
+
+		expectedSchema := envcheck.Config{
+			Vars: []envcheck.VarEntry{
+				{Name: "PORT", Type: envcheck.IntType, Required: true, DefaultValue: 8080},
+				{Name: "RATIO", Type: envcheck.FloatType},
+				{Name: "DEBUG", Type: envcheck.BoolType, DefaultValue: false},
+				{Name: "NAME", Type: envcheck.StringType, Pattern: StringPtr("^[a-z]+$")},
+				{Name: "EXTRA", Type: envcheck.AnyType},
+				{Name: "LEVEL", Type: envcheck.StringType},
+			},
+		}
+
+		if !reflect.DeepEqual(schema, expectedSchema) {
+			t.Errorf("expected schema: %v\n Actual schema: %v", expectedSchema, schema)
+		}
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		generated := envcheck.Schema(
+			envcheck.Var("PORT").Int(),
+			envcheck.Var("HOST"),
+		)
+		handwritten := envcheck.Schema(
+			envcheck.Var("DEBUG").Bool(),
+			envcheck.Var("PORT").Int().Required(),
+		)
+
+		expectedSchema := envcheck.Config{
+			Vars: []envcheck.VarEntry{
+				{Name: "PORT", Type: envcheck.IntType, Required: true},
+				{Name: "HOST", Type: envcheck.StringType},
+				{Name: "DEBUG", Type: envcheck.BoolType},
+			},
+		}
+
+		merged := envcheck.MergeConfigs(generated, handwritten)
+		if !reflect.DeepEqual(merged, expectedSchema) {
+			t.Errorf("expected schema: %v\n Actual schema: %v", expectedSchema, merged)
+		}
+	})
+
+	t.Run("Load with schema built in code", func(t *testing.T) {
+		defer restoreEnv(saveAndClearEnv([]string{"PORT", "NAME"}))
+
+		createFilesInTempDir(t, "this is not a valid schema: [", "NAME=Service")
+
+		schema := envcheck.Schema(
+			envcheck.Var("PORT").Int().Required().Default(8080),
+			envcheck.Var("NAME").Pattern("^[a-z]+$"),
+		)
+
+		_, err := envcheck.Load(envcheck.WithSchema(schema))
+		if err == nil {
+			t.Fatal("expected NAME to fail pattern validation")
+		}
+
+		const expectedError = "variable NAME does not match pattern ^[a-z]+$"
+		if !strings.Contains(err.Error(), expectedError) {
+			t.Fatalf("expected \"%v\"\nto contain \"%v\"", err.Error(), expectedError)
+		}
+
+		for _, key := range []string{"PORT", "NAME"} {
+			if value, isSet := os.LookupEnv(key); isSet {
+				t.Errorf("environment variable %s must not be set, but it is %s", key, value)
+			}
+		}
+	})
+
+	t.Run("Schema without var name", func(t *testing.T) {
+		createFilesInTempDir(t, "", "")
+
+		_, err := envcheck.Load(envcheck.WithSchema(envcheck.Schema(envcheck.Var(""))))
+		if err == nil || !strings.Contains(err.Error(), "config has var without name") {
+			t.Fatalf("expected error about var without name, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package envcheck
+
+import (
+	"errors"
+	"os"
+
+	"github.com/jogang0304/envcheck/internal"
+)
+
+/*
+This function fills struct pointed by v with values from os.env, usually after Load succeeded.
+Options select the schema the same way as for Load.
+
+Fields are bound to vars with `env:"NAME"` tags. Fields of embedded structs are bound the same way,
+other nested structs and pointers to them must have an `envPrefix:"PREFIX_"` tag, which is added to
+names of their vars; other fields are left as they are. Every bound var must be declared in the
+schema and the field type must match the declared type: string, signed or unsigned integer, float
+and bool fields for string, int, float and bool vars, any of them for any vars. Network vars fill
+url.URL, HostPort, netip.Addr and netip.Prefix fields for url, hostport, ip and cidr vars, or
+string fields with the value as is; port vars fill integer fields. Time vars fill time.Duration,
+time.Time, *time.Location and CronSchedule fields for duration, time, timezone and cron vars, or
+string fields. Size vars fill integer fields with bytes and quantity vars fill float fields with
+the value in their declared unit. List vars fill slices and map vars fill maps with string keys,
+whose elements match the element type of the var. Json vars fill fields of any type which their
+value decodes to with encoding/json. Pointer fields stay nil if the var is not set, pointers to
+nested structs stay nil if none of their vars is set. Slice fields take comma-separated values of
+string and any vars.
+
+	type Config struct {
+		Port  int     `env:"PORT"`
+		Debug *bool   `env:"DEBUG"`
+		DB    struct {
+			Host string `env:"HOST"`
+		} `envPrefix:"DB_"`
+	}
+*/
+func Unmarshal(v any, opts ...Option) error {
+	o := newOptions(opts)
+
+	root, err := projectRoot(o)
+	if err != nil {
+		return errors.Join(errors.New("failed to find project root"), err)
+	}
+
+	config, err := loadSchema(o, root, fileReader(o))
+	if err != nil {
+		return errors.Join(errors.New("failed to get config"), err)
+	}
+
+	err = internal.Unmarshal(&config, os.LookupEnv, v)
+	if err != nil {
+		return errors.Join(errors.New("failed to unmarshal env"), err)
+	}
+
+	return nil
+}
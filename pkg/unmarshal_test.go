@@ -0,0 +1,69 @@
+package envcheck_test
+
+import (
+	"strings"
+	"testing"
+
+	envcheck "github.com/jogang0304/envcheck/pkg"
+)
+
+func TestUnmarshal(t *testing.T) {
+	const configFileContent = `
+vars:
+  - name: PORT
+    type: int
+    default_value: 8080
+  - name: DB_HOST
+    required: true
+    type: string
+  - name: DEBUG
+    type: bool
+`
+	const envFileContent = `
+DB_HOST=db
+`
+
+	type settings struct {
+		Port  int   `env:"PORT"`
+		Debug *bool `env:"DEBUG"`
+		DB    struct {
+			Host string `env:"HOST"`
+		} `envPrefix:"DB_"`
+	}
+
+	t.Run("After Load", func(t *testing.T) {
+		defer restoreEnv(saveAndClearEnv([]string{"PORT", "DB_HOST", "DEBUG"}))
+
+		createFilesInTempDir(t, configFileContent, envFileContent)
+
+		if _, err := envcheck.Load(); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		var s settings
+		if err := envcheck.Unmarshal(&s); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if s.Port != 8080 || s.DB.Host != "db" || s.Debug != nil {
+			t.Errorf("unexpected result %+v", s)
+		}
+	})
+
+	t.Run("Field does not match schema", func(t *testing.T) {
+		createFilesInTempDir(t, configFileContent, envFileContent)
+
+		var s struct {
+			Port bool `env:"PORT"`
+		}
+		err := envcheck.Unmarshal(&s)
+		if err == nil {
+			t.Fatal("expected to get an error")
+		}
+
+		const expectedError = "field Port: type bool does not match var PORT of type int"
+		if !strings.Contains(err.Error(), expectedError) {
+			t.Fatalf("expected \"%v\"\nto contain \"%v\"", err.Error(), expectedError)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package envcheck
+
+import (
+	"github.com/jogang0304/envcheck/internal"
+)
+
+/*
+This function validates env against schema without touching os.env, so it can check the
+environment of another service or run in parallel tests. Unset vars get their default values,
+then required vars, types, patterns and allowed values are checked, see WithFailFast. env itself
+is not modified.
+The returned Env holds the resolved values of the declared vars. If validation fails, it is
+returned together with the error: Lookup gives the values as they are set and typed accessors give
+zero values for values which can not be parsed. If the schema is invalid, Env is nil.
+
+	env, err := envcheck.Validate(schema, map[string]string{"PORT": "8080"})
+*/
+func Validate(schema Config, env map[string]string, opts ...Option) (*Env, error) {
+	o := newOptions(opts)
+
+	config, err := checkSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := internal.Validate(&config, env, internal.ValidateOptions{FailFast: o.failFast})
+	if err != nil {
+		partial, _ := newEnv(config, internal.LookupInMap(resolved))
+		return partial, err
+	}
+
+	return newEnv(config, internal.LookupInMap(resolved))
+}
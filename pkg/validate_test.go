@@ -0,0 +1,108 @@
+package envcheck_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	envcheck "github.com/jogang0304/envcheck/pkg"
+)
+
+func TestValidate(t *testing.T) {
+	schema := envcheck.Schema(
+		envcheck.Var("VALIDATE_PORT").Int().Required().Default(8080),
+		envcheck.Var("VALIDATE_HOST").Required().Pattern("^[a-z.]+$"),
+	)
+
+	t.Run("Valid env", func(t *testing.T) {
+		t.Parallel()
+
+		env, err := envcheck.Validate(schema, map[string]string{"VALIDATE_HOST": "localhost"})
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if port := env.Int("VALIDATE_PORT"); port != 8080 {
+			t.Errorf("expected VALIDATE_PORT to be 8080, got %d", port)
+		}
+		if host := env.String("VALIDATE_HOST"); host != "localhost" {
+			t.Errorf("expected VALIDATE_HOST to be localhost, got %s", host)
+		}
+
+		if _, isSet := os.LookupEnv("VALIDATE_PORT"); isSet {
+			t.Error("Validate must not set vars in os.env")
+		}
+	})
+
+	t.Run("Invalid env", func(t *testing.T) {
+		t.Parallel()
+
+		env, err := envcheck.Validate(schema, map[string]string{
+			"VALIDATE_HOST": "LOCALHOST",
+			"VALIDATE_PORT": "http",
+		})
+		if err == nil {
+			t.Fatal("expected to get an error")
+		}
+		if env == nil {
+			t.Fatal("expected resolved values to be returned with the error")
+		}
+		if port, _ := env.Lookup("VALIDATE_PORT"); port != "http" || env.Int("VALIDATE_PORT") != 0 {
+			t.Errorf("expected VALIDATE_PORT to be set to http without int value, got %s", port)
+		}
+		if host := env.String("VALIDATE_HOST"); host != "LOCALHOST" {
+			t.Errorf("expected VALIDATE_HOST to be LOCALHOST, got %s", host)
+		}
+
+		expectedErrors := []string{
+			"var VALIDATE_PORT is not a valid int",
+			"variable VALIDATE_HOST does not match pattern",
+		}
+		for _, expectedError := range expectedErrors {
+			if !strings.Contains(err.Error(), expectedError) {
+				t.Errorf("expected \"%v\"\nto contain \"%v\"", err.Error(), expectedError)
+			}
+		}
+	})
+
+	t.Run("Fail fast", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := envcheck.Validate(schema, map[string]string{
+			"VALIDATE_HOST": "LOCALHOST",
+			"VALIDATE_PORT": "http",
+		}, envcheck.WithFailFast())
+		if err == nil {
+			t.Fatal("expected to get an error")
+		}
+
+		if !strings.Contains(err.Error(), "failed to validate var types") {
+			t.Fatalf("expected \"%v\" to report types", err.Error())
+		}
+		if strings.Contains(err.Error(), "pattern") {
+			t.Fatalf("expected \"%v\" not to report patterns", err.Error())
+		}
+	})
+
+	t.Run("Invalid schema", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := envcheck.Validate(envcheck.Schema(envcheck.Var("")), nil)
+		if err == nil || !strings.Contains(err.Error(), "config has var without name") {
+			t.Fatalf("expected error about var without name, got %v", err)
+		}
+	})
+
+	t.Run("Invalid schema of unset var", func(t *testing.T) {
+		t.Parallel()
+
+		broken := envcheck.Schema(envcheck.Var("VALIDATE_TIMEOUT").Int().Min("1s"))
+		_, err := envcheck.Validate(broken, nil)
+
+		var schemaErr *envcheck.SchemaError
+		if !errors.As(err, &schemaErr) || !strings.Contains(err.Error(), "has min or max") {
+			t.Fatalf("expected SchemaError about min, got %v", err)
+		}
+	})
+}